@@ -0,0 +1,352 @@
+package bip32
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/mr-tron/base58"
+	"golang.org/x/crypto/ripemd160"
+)
+
+// BIP-0032: https://github.com/bitcoin/bips/blob/master/bip-0032.mediawiki
+// This BIP describes hierarchical deterministic wallets (or "HD Wallets"): wallets which can be shared partially or entirely
+// with different systems, each with or without the ability to spend coins.
+
+const (
+	HardenedKeyStart uint32 = 0x80000000 // 2^31
+
+	MinSeedLen = 16 // 128 bits
+	MaxSeedLen = 64 // 512 bits
+
+	serializedKeyLen = 4 + 1 + 4 + 4 + 32 + 33 // version + depth + parent fingerprint + child number + chain code + key
+)
+
+var (
+	// mainnet version bytes
+	// https://github.com/bitcoin/bips/blob/master/bip-0032.mediawiki#serialization-format
+	PrivateVersion = [4]byte{0x04, 0x88, 0xad, 0xe4} // xprv
+	PublicVersion  = [4]byte{0x04, 0x88, 0xb2, 0x1e} // xpub
+
+	masterKeySecret = []byte("Bitcoin seed")
+)
+
+var (
+	ErrInvalidSeedLen      = fmt.Errorf("seed length must be between %d and %d bytes", MinSeedLen, MaxSeedLen)
+	ErrInvalidKey          = errors.New("invalid key: derived key is zero or not less than the curve order")
+	ErrDeriveHardenedPub   = errors.New("cannot derive a hardened child from a public key")
+	ErrNotPrivateKey       = errors.New("extended key is not a private key")
+	ErrInvalidChecksum     = errors.New("invalid extended key checksum")
+	ErrInvalidKeyLen       = errors.New("invalid extended key length")
+	ErrUnknownVersion      = errors.New("unknown extended key version")
+	ErrMaxDepth            = errors.New("cannot derive a key with more than 255 indices in its path")
+	ErrInvalidPath         = errors.New("invalid derivation path")
+	ErrInvalidPublicKey    = errors.New("invalid public key")
+	ErrInvalidMasterFields = errors.New("invalid master key: non-zero parent fingerprint or child number")
+)
+
+// ExtendedKey is a BIP-0032 extended private or public key.
+type ExtendedKey struct {
+	version           [4]byte
+	depth             uint8
+	parentFingerprint [4]byte
+	childNumber       uint32
+	chainCode         []byte // 32 bytes
+	key               []byte // private: 32 bytes, public: 33 bytes (compressed)
+	isPrivate         bool
+}
+
+// NewMasterKey creates a master extended private key from a seed, e.g. the output of bip39.CreateSeedFromMnemonic.
+func NewMasterKey(seed []byte) (*ExtendedKey, error) {
+	if len(seed) < MinSeedLen || len(seed) > MaxSeedLen {
+		return nil, ErrInvalidSeedLen
+	}
+	mac := hmac.New(sha512.New, masterKeySecret)
+	_, _ = mac.Write(seed)
+	sum := mac.Sum(nil)
+	secretKey, chainCode := sum[:32], sum[32:]
+
+	if !isValidPrivateKey(secretKey) {
+		return nil, ErrInvalidKey
+	}
+	return &ExtendedKey{
+		version:   PrivateVersion,
+		chainCode: chainCode,
+		key:       secretKey,
+		isPrivate: true,
+	}, nil
+}
+
+// IsPrivate reports whether the extended key is a private key.
+func (k *ExtendedKey) IsPrivate() bool {
+	return k.isPrivate
+}
+
+// Depth returns the number of derivations from the master key.
+func (k *ExtendedKey) Depth() uint8 {
+	return k.depth
+}
+
+// ChildNumber returns the index this key was derived with. Hardened indices include HardenedKeyStart.
+func (k *ExtendedKey) ChildNumber() uint32 {
+	return k.childNumber
+}
+
+// ParentFingerprint returns the fingerprint of the parent key (zero for a master key).
+func (k *ExtendedKey) ParentFingerprint() [4]byte {
+	return k.parentFingerprint
+}
+
+// ChainCode returns a copy of the 32-byte chain code.
+func (k *ExtendedKey) ChainCode() []byte {
+	return bytes.Clone(k.chainCode)
+}
+
+// Fingerprint returns the first 4 bytes of HASH160 of the compressed public key.
+func (k *ExtendedKey) Fingerprint() [4]byte {
+	var fp [4]byte
+	copy(fp[:], hash160(k.publicKeyBytes()))
+	return fp
+}
+
+// Child derives the child extended key at the given index.
+// Indices >= HardenedKeyStart derive hardened children, which requires a private key.
+func (k *ExtendedKey) Child(index uint32) (*ExtendedKey, error) {
+	if k.depth == 255 {
+		return nil, ErrMaxDepth
+	}
+	isHardened := index >= HardenedKeyStart
+	if isHardened && !k.isPrivate {
+		return nil, ErrDeriveHardenedPub
+	}
+
+	// hardened: 0x00 || ser256(kpar) || ser32(i)
+	// normal:   serP(point(kpar)) || ser32(i)
+	data := make([]byte, 0, 37)
+	if isHardened {
+		data = append(data, 0x00)
+		data = append(data, k.key...)
+	} else {
+		data = append(data, k.publicKeyBytes()...)
+	}
+	data = binary.BigEndian.AppendUint32(data, index)
+
+	mac := hmac.New(sha512.New, k.chainCode)
+	_, _ = mac.Write(data)
+	sum := mac.Sum(nil)
+	il, chainCode := sum[:32], sum[32:]
+
+	ilNum := new(big.Int).SetBytes(il)
+	if ilNum.Cmp(crypto.S256().Params().N) >= 0 {
+		return nil, ErrInvalidKey
+	}
+
+	var childKey []byte
+	if k.isPrivate {
+		keyNum := new(big.Int).SetBytes(k.key)
+		keyNum.Add(keyNum, ilNum)
+		keyNum.Mod(keyNum, crypto.S256().Params().N)
+		if keyNum.Sign() == 0 {
+			return nil, ErrInvalidKey
+		}
+		childKey = make([]byte, 32)
+		keyNum.FillBytes(childKey)
+	} else {
+		pub, err := crypto.DecompressPubkey(k.key)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidPublicKey, err)
+		}
+		curve := crypto.S256()
+		ilx, ily := curve.ScalarBaseMult(il)
+		x, y := curve.Add(ilx, ily, pub.X, pub.Y)
+		if x.Sign() == 0 && y.Sign() == 0 {
+			return nil, ErrInvalidKey
+		}
+		childKey = crypto.CompressPubkey(&ecdsa.PublicKey{Curve: curve, X: x, Y: y})
+	}
+
+	return &ExtendedKey{
+		version:           k.version,
+		depth:             k.depth + 1,
+		parentFingerprint: k.Fingerprint(),
+		childNumber:       index,
+		chainCode:         chainCode,
+		key:               childKey,
+		isPrivate:         k.isPrivate,
+	}, nil
+}
+
+// DerivePath derives the descendant key for a path such as "m/44'/60'/0'/0/5".
+// The leading "m" is optional, and hardened indices may be written with ', h or H.
+func (k *ExtendedKey) DerivePath(path string) (*ExtendedKey, error) {
+	indices, err := ParsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	key := k
+	for _, index := range indices {
+		key, err = key.Child(index)
+		if err != nil {
+			return nil, fmt.Errorf("derive %s: %w", path, err)
+		}
+	}
+	return key, nil
+}
+
+// ParsePath parses a derivation path like "m/44'/60'/0'/0/5" into child indices.
+func ParsePath(path string) ([]uint32, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return nil, fmt.Errorf("%w: empty path", ErrInvalidPath)
+	}
+	segments := strings.Split(path, "/")
+	if segments[0] == "m" || segments[0] == "M" {
+		segments = segments[1:]
+	}
+
+	indices := make([]uint32, 0, len(segments))
+	for _, segment := range segments {
+		hardened := false
+		if s, ok := strings.CutSuffix(segment, "'"); ok {
+			segment, hardened = s, true
+		} else if s, ok := strings.CutSuffix(segment, "h"); ok {
+			segment, hardened = s, true
+		} else if s, ok := strings.CutSuffix(segment, "H"); ok {
+			segment, hardened = s, true
+		}
+		index, err := strconv.ParseUint(segment, 10, 32)
+		if err != nil || uint32(index) >= HardenedKeyStart {
+			return nil, fmt.Errorf("%w: invalid index %q in %q", ErrInvalidPath, segment, path)
+		}
+		if hardened {
+			index += uint64(HardenedKeyStart)
+		}
+		indices = append(indices, uint32(index))
+	}
+	return indices, nil
+}
+
+// Neuter returns the extended public key corresponding to the extended key.
+func (k *ExtendedKey) Neuter() *ExtendedKey {
+	if !k.isPrivate {
+		return k
+	}
+	return &ExtendedKey{
+		version:           PublicVersion,
+		depth:             k.depth,
+		parentFingerprint: k.parentFingerprint,
+		childNumber:       k.childNumber,
+		chainCode:         k.chainCode,
+		key:               k.publicKeyBytes(),
+		isPrivate:         false,
+	}
+}
+
+// ECPrivKey returns the secp256k1 private key of an extended private key.
+func (k *ExtendedKey) ECPrivKey() (*ecdsa.PrivateKey, error) {
+	if !k.isPrivate {
+		return nil, ErrNotPrivateKey
+	}
+	return crypto.ToECDSA(k.key)
+}
+
+// ECPubKey returns the secp256k1 public key of the extended key.
+func (k *ExtendedKey) ECPubKey() (*ecdsa.PublicKey, error) {
+	return crypto.DecompressPubkey(k.publicKeyBytes())
+}
+
+// String returns the base58check serialization of the extended key (xprv... or xpub...).
+func (k *ExtendedKey) String() string {
+	data := make([]byte, 0, serializedKeyLen+4)
+	data = append(data, k.version[:]...)
+	data = append(data, k.depth)
+	data = append(data, k.parentFingerprint[:]...)
+	data = binary.BigEndian.AppendUint32(data, k.childNumber)
+	data = append(data, k.chainCode...)
+	if k.isPrivate {
+		data = append(data, 0x00)
+	}
+	data = append(data, k.key...)
+	data = append(data, checksum(data)...)
+	return base58.Encode(data)
+}
+
+// ParseExtendedKey parses a base58check serialized extended key (xprv... or xpub...).
+func ParseExtendedKey(s string) (*ExtendedKey, error) {
+	decoded, err := base58.Decode(s)
+	if err != nil {
+		return nil, fmt.Errorf("base58 decode: %w", err)
+	}
+	if len(decoded) != serializedKeyLen+4 {
+		return nil, ErrInvalidKeyLen
+	}
+	payload, sum := decoded[:serializedKeyLen], decoded[serializedKeyLen:]
+	if !bytes.Equal(checksum(payload), sum) {
+		return nil, ErrInvalidChecksum
+	}
+
+	k := &ExtendedKey{
+		depth:       payload[4],
+		childNumber: binary.BigEndian.Uint32(payload[9:13]),
+		chainCode:   bytes.Clone(payload[13:45]),
+	}
+	copy(k.version[:], payload[:4])
+	copy(k.parentFingerprint[:], payload[5:9])
+	if k.depth == 0 && (k.parentFingerprint != [4]byte{} || k.childNumber != 0) {
+		return nil, ErrInvalidMasterFields
+	}
+
+	keyData := payload[45:]
+	switch k.version {
+	case PrivateVersion:
+		if keyData[0] != 0x00 || !isValidPrivateKey(keyData[1:]) {
+			return nil, ErrInvalidKey
+		}
+		k.key = bytes.Clone(keyData[1:])
+		k.isPrivate = true
+	case PublicVersion:
+		if _, err := crypto.DecompressPubkey(keyData); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidPublicKey, err)
+		}
+		k.key = bytes.Clone(keyData)
+	default:
+		return nil, ErrUnknownVersion
+	}
+	return k, nil
+}
+
+func (k *ExtendedKey) publicKeyBytes() []byte {
+	if !k.isPrivate {
+		return k.key
+	}
+	curve := crypto.S256()
+	x, y := curve.ScalarBaseMult(k.key)
+	return crypto.CompressPubkey(&ecdsa.PublicKey{Curve: curve, X: x, Y: y})
+}
+
+func isValidPrivateKey(key []byte) bool {
+	num := new(big.Int).SetBytes(key)
+	return num.Sign() > 0 && num.Cmp(crypto.S256().Params().N) < 0
+}
+
+func hash160(data []byte) []byte {
+	h := sha256.Sum256(data)
+	r := ripemd160.New()
+	_, _ = r.Write(h[:])
+	return r.Sum(nil)
+}
+
+func checksum(data []byte) []byte {
+	h1 := sha256.Sum256(data)
+	h2 := sha256.Sum256(h1[:])
+	return h2[:4]
+}
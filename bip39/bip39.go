@@ -0,0 +1,347 @@
+package bip39
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"slices"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/text/unicode/norm"
+
+	"github.com/15ho/wallet-utils-go/bip39/wordlist"
+)
+
+// BIP-0039: https://github.com/bitcoin/bips/blob/master/bip-0039.mediawiki
+// This BIP describes the implementation of a mnemonic code or mnemonic sentence -- a group of easy to remember words -- for the generation of deterministic wallets.
+// It consists of two parts: generating the mnemonic and converting it into a binary seed. This seed can be later used to generate deterministic wallets using BIP-0032 or similar methods.
+
+// python implementation: https://github.com/trezor/python-mnemonic/tree/b57a5ad77a981e743f4167ab2f7927a55c1e82a8
+
+// randReader is the entropy source of Generate.
+var randReader io.Reader = rand.Reader
+
+// Languages lists the languages of the bundled word lists.
+var Languages = []string{
+	"english",
+	"chinese_simplified",
+	"chinese_traditional",
+	"czech",
+	"french",
+	"italian",
+	"japanese",
+	"korean",
+	"portuguese",
+	"russian",
+	"spanish",
+	"turkish",
+}
+
+var (
+	ErrInvalidWordCount  = errors.New("invalid mnemonic word count")
+	ErrUnknownWord       = errors.New("unknown mnemonic word")
+	ErrInvalidChecksum   = errors.New("invalid mnemonic checksum")
+	ErrUnknownLanguage   = errors.New("unable to detect mnemonic language")
+	ErrAmbiguousLanguage = errors.New("ambiguous mnemonic language")
+)
+
+type MnemonicGenerator struct {
+	language  string
+	wordList  []string
+	wordIndex map[string]int // normalized word -> index
+	delimiter string
+}
+
+// NewMnemonicGenerator returns a new MnemonicGenerator for the given language.
+func NewMnemonicGenerator(language string) (*MnemonicGenerator, error) {
+	var wordListStr string
+	delimiter := " "
+	switch strings.ToLower(language) {
+	case "english":
+		wordListStr = wordlist.English
+	case "chinese_simplified":
+		wordListStr = wordlist.ChineseSimplified
+	case "chinese_traditional":
+		wordListStr = wordlist.ChineseTraditional
+	case "spanish":
+		wordListStr = wordlist.Spanish
+	case "french":
+		wordListStr = wordlist.French
+	case "italian":
+		wordListStr = wordlist.Italian
+	case "japanese":
+		wordListStr = wordlist.Japanese
+		delimiter = "\u3000"
+	case "korean":
+		wordListStr = wordlist.Korean
+	case "portuguese":
+		wordListStr = wordlist.Portuguese
+	case "russian":
+		wordListStr = wordlist.Russian
+	case "turkish":
+		wordListStr = wordlist.Turkish
+	case "czech":
+		wordListStr = wordlist.Czech
+	default:
+		return nil, fmt.Errorf("language %s not supported", language)
+	}
+
+	wordList := strings.Split(strings.TrimSpace(wordListStr), "\n")
+	if len(wordList) != 2048 {
+		return nil, fmt.Errorf("invalid word list length: %d", len(wordList))
+	}
+	wordIndex := make(map[string]int, len(wordList))
+	for i, word := range wordList {
+		wordIndex[normalizeString(word)] = i
+	}
+	return &MnemonicGenerator{
+		language:  strings.ToLower(language),
+		wordList:  wordList,
+		wordIndex: wordIndex,
+		delimiter: delimiter,
+	}, nil
+}
+
+// Language returns the language of the word list.
+func (mg *MnemonicGenerator) Language() string {
+	return mg.language
+}
+
+// Generate Create a new mnemonic using a random generated number as entropy.
+// As defined in BIP39, the entropy must be a multiple of 32 bits, and its size must be between 128 and 256 bits.
+// Therefore the possible values for `strength` are 128, 160, 192, 224 and 256.
+// If not provided, the default entropy length will be set to 128 bits.
+// The return is a list of words that encodes the generated entropy.
+func (mg *MnemonicGenerator) Generate(strengthOption ...int) (string, error) {
+	strength := 128
+	if len(strengthOption) > 0 {
+		strength = strengthOption[0]
+	}
+	if !slices.Contains([]int{128, 160, 192, 224, 256}, strength) {
+		return "", fmt.Errorf("invalid strength: %d", strength)
+	}
+	data := make([]byte, strength/8)
+	if _, err := io.ReadFull(randReader, data); err != nil {
+		return "", fmt.Errorf("read random entropy: %w", err)
+	}
+	return mg.toMnemonic(data)
+}
+
+// FromEntropy Create a mnemonic from caller-supplied entropy, e.g. from dice rolls or test fixtures.
+// The entropy length must be 16, 20, 24, 28 or 32 bytes, the same mnemonic is always returned for the same entropy.
+func (mg *MnemonicGenerator) FromEntropy(entropy []byte) (string, error) {
+	return mg.toMnemonic(entropy)
+}
+
+func (mg *MnemonicGenerator) toMnemonic(data []byte) (string, error) {
+	if !slices.Contains([]int{16, 20, 24, 28, 32}, len(data)) {
+		return "", fmt.Errorf("invalid data length: %d", len(data))
+	}
+	h256 := sha256.Sum256(data)
+	h := hex.EncodeToString(h256[:])
+
+	b1 := fmt.Sprintf("%0*b", len(data)*8, new(big.Int).SetBytes(data))
+
+	hInt, _ := new(big.Int).SetString(h, 16)
+	b2 := fmt.Sprintf("%0*b", 256, hInt)[:len(data)*8/32]
+
+	b := b1 + b2
+	words := make([]string, len(b)/11)
+	for i := 0; i < len(words); i++ {
+		start := i * 11
+		end := start + 11
+		bInt, _ := new(big.Int).SetString(b[start:end], 2)
+		words[i] = mg.wordList[bInt.Int64()]
+	}
+	return strings.Join(words, mg.delimiter), nil
+}
+
+// ToEntropy Convert a mnemonic back to the entropy it encodes, it is the inverse of FromEntropy.
+// The checksum is verified, an unknown word is reported with its position.
+func (mg *MnemonicGenerator) ToEntropy(mnemonic string) ([]byte, error) {
+	words := splitMnemonic(mnemonic)
+	if !slices.Contains([]int{12, 15, 18, 21, 24}, len(words)) {
+		return nil, fmt.Errorf("%w: %d, must be 12, 15, 18, 21 or 24", ErrInvalidWordCount, len(words))
+	}
+
+	// concatenated bits = entropy || checksum, 11 bits per word
+	b := new(big.Int)
+	for i, word := range words {
+		idx, ok := mg.wordIndex[word]
+		if !ok {
+			return nil, fmt.Errorf("%w: %q at position %d is not in the %s word list", ErrUnknownWord, word, i+1, mg.language)
+		}
+		b.Lsh(b, 11)
+		b.Or(b, big.NewInt(int64(idx)))
+	}
+
+	checksumBits := uint(len(words) * 11 / 33)
+	entropyBits := uint(len(words)*11) - checksumBits
+	checksum := new(big.Int).And(b, new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), checksumBits), big.NewInt(1)))
+	entropy := make([]byte, entropyBits/8)
+	new(big.Int).Rsh(b, checksumBits).FillBytes(entropy)
+
+	h256 := sha256.Sum256(entropy)
+	expectChecksum := new(big.Int).Rsh(new(big.Int).SetBytes(h256[:]), 256-checksumBits)
+	if checksum.Cmp(expectChecksum) != 0 {
+		return nil, ErrInvalidChecksum
+	}
+	return entropy, nil
+}
+
+// Validate checks the word count, that every word is in the word list and the checksum.
+func (mg *MnemonicGenerator) Validate(mnemonic string) error {
+	_, err := mg.ToEntropy(mnemonic)
+	return err
+}
+
+// DetectLanguage returns the language whose word list contains every word of the mnemonic.
+// If several word lists contain all the words (e.g. chinese_simplified and chinese_traditional),
+// the language the checksum is valid for is returned. If the checksum is valid for several of them
+// and they all decode to the same entropy, the first one in Languages is returned, otherwise
+// ErrAmbiguousLanguage. If the checksum is valid for none, the first of them in Languages is returned.
+func DetectLanguage(mnemonic string) (string, error) {
+	mg, err := detectLanguage(mnemonic)
+	if err != nil {
+		return "", err
+	}
+	return mg.language, nil
+}
+
+// languageGenerators are the generators of Languages used by the language detection,
+// their word lists are split and indexed once.
+var languageGenerators = sync.OnceValues(func() ([]*MnemonicGenerator, error) {
+	generators := make([]*MnemonicGenerator, 0, len(Languages))
+	for _, language := range Languages {
+		mg, err := NewMnemonicGenerator(language)
+		if err != nil {
+			return nil, err
+		}
+		generators = append(generators, mg)
+	}
+	return generators, nil
+})
+
+func detectLanguage(mnemonic string) (*MnemonicGenerator, error) {
+	words := splitMnemonic(mnemonic)
+	if len(words) == 0 {
+		return nil, fmt.Errorf("%w: empty mnemonic", ErrUnknownLanguage)
+	}
+
+	generators, err := languageGenerators()
+	if err != nil {
+		return nil, err
+	}
+	var (
+		candidates []*MnemonicGenerator
+		best       *MnemonicGenerator
+		bestKnown  int
+	)
+	for _, mg := range generators {
+		known := 0
+		for _, word := range words {
+			if _, ok := mg.wordIndex[word]; ok {
+				known++
+			}
+		}
+		if known == len(words) {
+			candidates = append(candidates, mg)
+		}
+		if known > bestKnown {
+			best, bestKnown = mg, known
+		}
+	}
+
+	switch len(candidates) {
+	case 0:
+		if best == nil {
+			return nil, fmt.Errorf("%w: no word matches any word list", ErrUnknownLanguage)
+		}
+		// report the unknown word against the closest language
+		_, err := best.ToEntropy(mnemonic)
+		return nil, fmt.Errorf("%w: %w", ErrUnknownLanguage, err)
+	case 1:
+		return candidates[0], nil
+	}
+
+	valid := slices.DeleteFunc(slices.Clone(candidates), func(mg *MnemonicGenerator) bool {
+		return mg.Validate(mnemonic) != nil
+	})
+	switch len(valid) {
+	case 0:
+		// the checksum is invalid in every candidate, let the caller report it against the first one
+		return candidates[0], nil
+	case 1:
+		return valid[0], nil
+	}
+	// words at the same index in several word lists (e.g. the shared characters of
+	// chinese_simplified and chinese_traditional) decode to the same entropy
+	entropy, _ := valid[0].ToEntropy(mnemonic)
+	ambiguous := slices.ContainsFunc(valid[1:], func(mg *MnemonicGenerator) bool {
+		other, _ := mg.ToEntropy(mnemonic)
+		return !bytes.Equal(entropy, other)
+	})
+	if !ambiguous {
+		return valid[0], nil
+	}
+	languages := make([]string, 0, len(valid))
+	for _, mg := range valid {
+		languages = append(languages, mg.language)
+	}
+	return nil, fmt.Errorf("%w: %s", ErrAmbiguousLanguage, strings.Join(languages, ", "))
+}
+
+// Validate checks a mnemonic in any of the bundled languages, the language is detected automatically.
+func Validate(mnemonic string) error {
+	mg, err := detectLanguage(mnemonic)
+	if err != nil {
+		return err
+	}
+	return mg.Validate(mnemonic)
+}
+
+// ToEntropy converts a mnemonic in any of the bundled languages back to its entropy.
+func ToEntropy(mnemonic string) ([]byte, error) {
+	mg, err := detectLanguage(mnemonic)
+	if err != nil {
+		return nil, err
+	}
+	return mg.ToEntropy(mnemonic)
+}
+
+// CreateSeedFromMnemonic creates a seed from a mnemonic.
+func CreateSeedFromMnemonic(mnemonic string, passphraseOption ...string) []byte {
+	var passphrase string
+	if len(passphraseOption) > 0 {
+		passphrase = passphraseOption[0]
+	}
+	normalizedMnemonic := normalizeString(mnemonic)
+	normalizedPassphrase := normalizeString(passphrase)
+
+	salt := "mnemonic" + normalizedPassphrase
+
+	return pbkdf2.Key(
+		[]byte(normalizedMnemonic),
+		[]byte(salt),
+		2048,
+		64,
+		sha512.New,
+	)
+}
+
+func normalizeString(s string) string {
+	return norm.NFKD.String(s)
+}
+
+func splitMnemonic(mnemonic string) []string {
+	// NFKD maps the ideographic space used by japanese mnemonics to an ascii space
+	return strings.Fields(normalizeString(mnemonic))
+}
@@ -0,0 +1,252 @@
+package bip39
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewMnemonicGenerator(t *testing.T) {
+	t.Run("valid language", func(t *testing.T) {
+		for _, lang := range []string{
+			"english",
+			"chinese_simplified",
+			"chinese_traditional",
+			"czech",
+			"french",
+			"italian",
+			"japanese",
+			"korean",
+			"portuguese",
+			"russian",
+			"spanish",
+			"turkish",
+		} {
+			_, err := NewMnemonicGenerator(lang)
+			assert.NoError(t, err)
+		}
+	})
+	t.Run("invalid language", func(t *testing.T) {
+		_, err := NewMnemonicGenerator("invalid")
+		assert.Error(t, err)
+	})
+}
+
+func TestMnemonicGenerator(t *testing.T) {
+	lang := "english"
+	mg, err := NewMnemonicGenerator(lang)
+	assert.NoError(t, err)
+
+	t.Run(lang, func(t *testing.T) {
+		t.Run("generate mnemonic", func(t *testing.T) {
+			words, err := mg.Generate()
+			assert.NoError(t, err)
+			assert.Len(t, strings.Split(words, " "), 12)
+			t.Log(words)
+
+			for _, strength := range []int{128, 160, 192, 224, 256} {
+				words, err := mg.Generate(strength)
+				assert.NoError(t, err)
+				assert.Len(t, strings.Split(words, " "), strength/32*3)
+				t.Log(words)
+			}
+		})
+	})
+
+	lang = "japanese"
+	mg, err = NewMnemonicGenerator(lang)
+	assert.NoError(t, err)
+	t.Run(lang, func(t *testing.T) {
+		t.Run("generate mnemonic", func(t *testing.T) {
+			words, err := mg.Generate()
+			assert.NoError(t, err)
+			assert.Len(t, strings.Split(words, "\u3000"), 12)
+			t.Log(words)
+		})
+	})
+}
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, errors.New("entropy source unavailable")
+}
+
+func TestGenerateRandError(t *testing.T) {
+	mg, err := NewMnemonicGenerator("english")
+	assert.NoError(t, err)
+
+	randReader = failingReader{}
+	defer func() { randReader = rand.Reader }()
+
+	words, err := mg.Generate()
+	assert.ErrorContains(t, err, "entropy source unavailable")
+	assert.Empty(t, words)
+}
+
+func TestFromEntropy(t *testing.T) {
+	mg, err := NewMnemonicGenerator("english")
+	assert.NoError(t, err)
+
+	t.Run("deterministic", func(t *testing.T) {
+		entropy, _ := hex.DecodeString("9e885d952ad362caeb4efe34a8e91bd2")
+		words1, err := mg.FromEntropy(entropy)
+		assert.NoError(t, err)
+		words2, err := mg.FromEntropy(entropy)
+		assert.NoError(t, err)
+		assert.Equal(t, "ozone drill grab fiber curtain grace pudding thank cruise elder eight picnic", words1)
+		assert.Equal(t, words1, words2)
+	})
+
+	t.Run("invalid length", func(t *testing.T) {
+		for _, size := range []int{0, 15, 17, 33} {
+			_, err := mg.FromEntropy(make([]byte, size))
+			assert.Error(t, err)
+		}
+	})
+}
+
+func TestCreateSeedFromMnemonic(t *testing.T) {
+	mg, err := NewMnemonicGenerator("english")
+	assert.NoError(t, err)
+
+	t.Run("create", func(t *testing.T) {
+		words, err := mg.Generate()
+		assert.NoError(t, err)
+		t.Log(words)
+		seed := CreateSeedFromMnemonic(words)
+		assert.Len(t, seed, 64)
+		t.Log(seed, hex.EncodeToString(seed))
+		seed = CreateSeedFromMnemonic(words, "12347890")
+		assert.Len(t, seed, 64)
+		t.Log(seed, hex.EncodeToString(seed))
+	})
+
+	t.Run("validate", func(t *testing.T) {
+		words := "prefer judge blouse motor naive october legal labor exact sustain stuff direct"
+		expectSeed1, _ := hex.DecodeString("e2e576197f191c9ac9a13af747c9ee3104701a37a6f018438ec827147cfd674f6279adf0a209b8e7e3f6c7bd20e0be1ba6d20055aced88b5030296e1bdecb044")
+		expectSeed2, _ := hex.DecodeString("2ffd59fae330185352dd5079121a7957513eef7cad19c873e391341e8ec1b07ff4d2d07115f697460d65317b54f6e4de53f624fbb9c9009dcddab02551c27333")
+		seed2Passphrase := "151515888"
+
+		seed1 := CreateSeedFromMnemonic(words)
+		seed2 := CreateSeedFromMnemonic(words, seed2Passphrase)
+		assert.EqualValues(t, expectSeed1, seed1)
+		assert.EqualValues(t, expectSeed2, seed2)
+	})
+
+}
+
+// https://github.com/trezor/python-mnemonic/blob/master/vectors.json
+var englishVectors = []struct {
+	entropy  string
+	mnemonic string
+}{
+	{"00000000000000000000000000000000", "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"},
+	{"7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f", "legal winner thank year wave sausage worth useful legal winner thank yellow"},
+	{"80808080808080808080808080808080", "letter advice cage absurd amount doctor acoustic avoid letter advice cage above"},
+	{"ffffffffffffffffffffffffffffffff", "zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo wrong"},
+	{"9e885d952ad362caeb4efe34a8e91bd2", "ozone drill grab fiber curtain grace pudding thank cruise elder eight picnic"},
+	{"0000000000000000000000000000000000000000000000000000000000000000", "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon art"},
+	{"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff", "zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo vote"},
+	{"68a79eaca2324873eacc50cb9c6eca8cc68ea5d936f98787c60c7ebc74e6ce7c", "hamster diagram private dutch cause delay private meat slide toddler razor book happy fancy gospel tennis maple dilemma loan word shrug inflict delay length"},
+}
+
+func TestToEntropy(t *testing.T) {
+	mg, err := NewMnemonicGenerator("english")
+	assert.NoError(t, err)
+
+	for _, v := range englishVectors {
+		entropy, _ := hex.DecodeString(v.entropy)
+
+		words, err := mg.FromEntropy(entropy)
+		assert.NoError(t, err)
+		assert.Equal(t, v.mnemonic, words)
+
+		parsed, err := mg.ToEntropy(v.mnemonic)
+		assert.NoError(t, err)
+		assert.Equal(t, entropy, parsed)
+
+		parsed, err = ToEntropy(v.mnemonic)
+		assert.NoError(t, err)
+		assert.Equal(t, entropy, parsed)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		for _, v := range englishVectors {
+			assert.NoError(t, Validate(v.mnemonic))
+		}
+		// extra whitespace is ignored
+		assert.NoError(t, Validate("  legal winner thank year wave sausage\tworth useful legal winner thank yellow\n"))
+	})
+
+	t.Run("invalid checksum", func(t *testing.T) {
+		err := Validate("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon")
+		assert.ErrorIs(t, err, ErrInvalidChecksum)
+	})
+
+	t.Run("invalid word count", func(t *testing.T) {
+		err := Validate("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about")
+		assert.ErrorIs(t, err, ErrInvalidWordCount)
+	})
+
+	t.Run("unknown word", func(t *testing.T) {
+		err := Validate("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandonn abandon about")
+		assert.ErrorIs(t, err, ErrUnknownWord)
+		assert.ErrorContains(t, err, `"abandonn" at position 10`)
+
+		mg, _ := NewMnemonicGenerator("english")
+		err = mg.Validate("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon foo")
+		assert.ErrorIs(t, err, ErrUnknownWord)
+		assert.ErrorContains(t, err, `"foo" at position 12`)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		assert.ErrorIs(t, Validate(""), ErrUnknownLanguage)
+		assert.ErrorIs(t, Validate(strings.Repeat("xyzzy ", 12)), ErrUnknownLanguage)
+		assert.ErrorIs(t, Validate("hello world"), ErrInvalidWordCount)
+	})
+}
+
+func TestDetectLanguage(t *testing.T) {
+	for _, lang := range Languages {
+		t.Run(lang, func(t *testing.T) {
+			mg, err := NewMnemonicGenerator(lang)
+			assert.NoError(t, err)
+
+			entropy := sha256.Sum256([]byte(lang))
+			for _, size := range []int{16, 32} {
+				words, err := mg.FromEntropy(entropy[:size])
+				assert.NoError(t, err)
+
+				detected, err := DetectLanguage(words)
+				assert.NoError(t, err)
+				assert.Equal(t, lang, detected)
+
+				parsed, err := ToEntropy(words)
+				assert.NoError(t, err)
+				assert.Equal(t, entropy[:size], parsed)
+			}
+		})
+	}
+}
+
+func TestDetectLanguageSharedWords(t *testing.T) {
+	// every word is at the same index in chinese_simplified and chinese_traditional
+	mnemonic := "的 的 的 的 的 的 的 的 的 的 的 在"
+
+	detected, err := DetectLanguage(mnemonic)
+	assert.NoError(t, err)
+	assert.Equal(t, "chinese_simplified", detected)
+
+	assert.NoError(t, Validate(mnemonic))
+	entropy, err := ToEntropy(mnemonic)
+	assert.NoError(t, err)
+	assert.Equal(t, make([]byte, 16), entropy)
+}
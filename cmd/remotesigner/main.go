@@ -0,0 +1,57 @@
+// Command remotesigner is the reference remote signing server, it serves the keystore files of a directory over gRPC.
+//
+//	REMOTE_SIGNER_PASSPHRASE=... remotesigner -keystore ./keys -listen 127.0.0.1:50051
+//
+// The key id of a keystore file is its file name without the .json extension.
+// Bind it to localhost or put it behind TLS, the server does not authenticate clients.
+package main
+
+import (
+	"flag"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/15ho/wallet-utils-go/signer/remote"
+	"google.golang.org/grpc"
+)
+
+func main() {
+	listen := flag.String("listen", "127.0.0.1:50051", "listen address")
+	keystoreDir := flag.String("keystore", "", "directory of keystore v3 JSON files")
+	flag.Parse()
+
+	passphrase, ok := os.LookupEnv("REMOTE_SIGNER_PASSPHRASE")
+	if !ok || *keystoreDir == "" {
+		flag.Usage()
+		log.Fatal("-keystore and REMOTE_SIGNER_PASSPHRASE are required")
+	}
+
+	srv := remote.NewServer()
+	keyIDs, err := srv.LoadKeystoreDir(*keystoreDir, passphrase)
+	if err != nil {
+		log.Fatalf("load keystore: %v", err)
+	}
+	log.Printf("loaded keys: %v", keyIDs)
+
+	lis, err := net.Listen("tcp", *listen)
+	if err != nil {
+		log.Fatalf("listen: %v", err)
+	}
+	gs := grpc.NewServer()
+	remote.RegisterRemoteSignerServer(gs, srv)
+
+	go func() {
+		sig := make(chan os.Signal, 1)
+		signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+		<-sig
+		gs.GracefulStop()
+	}()
+
+	log.Printf("remote signer listening on %s", lis.Addr())
+	if err := gs.Serve(lis); err != nil {
+		log.Fatalf("serve: %v", err)
+	}
+}
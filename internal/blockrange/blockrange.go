@@ -0,0 +1,77 @@
+// Package blockrange parses ranges of blocks concurrently for the TxParsers of the chain packages.
+package blockrange
+
+import (
+	"context"
+	"fmt"
+	"iter"
+)
+
+// DefaultWorkers is the number of blocks parsed concurrently when no worker count is given.
+const DefaultWorkers = 8
+
+// Parse calls parse for every block from startBlock to endBlock (inclusive), at most workers blocks at a time,
+// and yields the results in block order. A failing block does not stop the range, its error is yielded in its place.
+// The iteration stops with ctx.Err() when ctx is done, the blocks in progress are cancelled when the caller stops early.
+func Parse[N ~int64 | ~uint64, T any](ctx context.Context, startBlock, endBlock N, workers int,
+	parse func(ctx context.Context, blockNumber N) (T, error)) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		if startBlock > endBlock {
+			yield(zero, fmt.Errorf("invalid block range [%d, %d]", startBlock, endBlock))
+			return
+		}
+		if workers < 1 {
+			workers = DefaultWorkers
+		}
+
+		type result struct {
+			v   T
+			err error
+		}
+		workCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		// results of the started blocks in block order, the one awaited by the loop below and workers-1 buffered
+		pending := make(chan chan result, workers-1)
+		var started bool // all blocks were started, read after pending is closed
+		go func() {
+			defer close(pending)
+			for blockNumber := startBlock; ; blockNumber++ {
+				future := make(chan result, 1)
+				select {
+				case pending <- future:
+				case <-workCtx.Done():
+					return
+				}
+				go func() {
+					v, err := parse(workCtx, blockNumber)
+					future <- result{v, err}
+				}()
+				if blockNumber == endBlock {
+					started = true
+					return
+				}
+			}
+		}()
+
+		for future := range pending {
+			var r result
+			select {
+			case r = <-future:
+			case <-ctx.Done():
+			}
+			if err := ctx.Err(); err != nil {
+				yield(zero, err)
+				return
+			}
+			if !yield(r.v, r.err) {
+				return
+			}
+		}
+		// the producer stopped before the end of the range
+		if !started {
+			yield(zero, ctx.Err())
+		}
+	}
+}
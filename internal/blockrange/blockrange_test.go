@@ -0,0 +1,105 @@
+package blockrange
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	ctx := context.Background()
+	errOdd := errors.New("odd block")
+
+	t.Run("ordered", func(t *testing.T) {
+		var running, maxRunning atomic.Int64
+		parse := func(_ context.Context, blockNumber int64) (int64, error) {
+			n := running.Add(1)
+			defer running.Add(-1)
+			for {
+				m := maxRunning.Load()
+				if n <= m || maxRunning.CompareAndSwap(m, n) {
+					break
+				}
+			}
+			time.Sleep(time.Duration(rand.IntN(3)) * time.Millisecond)
+			if blockNumber%2 == 1 {
+				return blockNumber, errOdd
+			}
+			return blockNumber, nil
+		}
+
+		next := int64(10)
+		for blockNumber, err := range Parse(ctx, int64(10), int64(60), 4, parse) {
+			assert.Equal(t, next, blockNumber)
+			if blockNumber%2 == 1 {
+				assert.ErrorIs(t, err, errOdd)
+			} else {
+				assert.NoError(t, err)
+			}
+			next++
+		}
+		assert.Equal(t, int64(61), next)
+		assert.LessOrEqual(t, maxRunning.Load(), int64(4))
+	})
+
+	t.Run("single block", func(t *testing.T) {
+		var blocks []uint64
+		for blockNumber, err := range Parse(ctx, uint64(7), uint64(7), 1, func(_ context.Context, n uint64) (uint64, error) { return n, nil }) {
+			assert.NoError(t, err)
+			blocks = append(blocks, blockNumber)
+		}
+		assert.Equal(t, []uint64{7}, blocks)
+	})
+
+	t.Run("invalid range", func(t *testing.T) {
+		for _, err := range Parse(ctx, 2, 1, 1, func(_ context.Context, n int64) (int64, error) { return n, nil }) {
+			assert.Error(t, err)
+		}
+	})
+
+	t.Run("break", func(t *testing.T) {
+		var parsed atomic.Int64
+		for blockNumber := range Parse(ctx, 0, 1000, 2, func(_ context.Context, n int64) (int64, error) {
+			parsed.Add(1)
+			return n, nil
+		}) {
+			if blockNumber == 3 {
+				break
+			}
+		}
+		assert.Less(t, parsed.Load(), int64(10))
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		cancelCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		var errs []error
+		for blockNumber, err := range Parse(cancelCtx, 0, 1000, 2, func(ctx context.Context, n int64) (int64, error) {
+			if n == 5 {
+				<-ctx.Done()
+				return n, ctx.Err()
+			}
+			return n, nil
+		}) {
+			if blockNumber == 4 {
+				cancel()
+			}
+			errs = append(errs, err)
+		}
+		assert.Len(t, errs, 6)
+		assert.ErrorIs(t, errs[5], context.Canceled)
+
+		// cancelled before the start
+		errs = nil
+		for _, err := range Parse(cancelCtx, 0, 1000, 2, func(_ context.Context, n int64) (int64, error) { return n, nil }) {
+			errs = append(errs, err)
+		}
+		assert.Len(t, errs, 1)
+		assert.ErrorIs(t, errs[0], context.Canceled)
+	})
+}
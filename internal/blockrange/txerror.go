@@ -0,0 +1,18 @@
+package blockrange
+
+import "fmt"
+
+// TxParseError is a transaction of a block that failed to parse, aliased by the chain packages.
+type TxParseError struct {
+	Index  int    // index of the transaction in the block
+	TxHash string // transaction hash (signature on Solana) // empty when the transaction could not be decoded
+	Err    error
+}
+
+func (e *TxParseError) Error() string {
+	return fmt.Sprintf("parse tx %d (%s): %v", e.Index, e.TxHash, e.Err)
+}
+
+func (e *TxParseError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,329 @@
+package keystore
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/scrypt"
+)
+
+// Web3 Secret Storage Definition (keystore v3): https://ethereum.org/developers/docs/data-structures-and-encoding/web3-secret-storage
+// The private key is encrypted with aes-128-ctr, the key is derived from the passphrase with scrypt or pbkdf2,
+// and keccak256(derivedKey[16:32] || ciphertext) is stored as the MAC to verify the passphrase.
+// Any raw private key can be stored: secp256k1 keys of Ethereum, Arbitrum and Tron, or ed25519 keys of Solana.
+
+const (
+	KDFScrypt = "scrypt"
+	KDFPBKDF2 = "pbkdf2"
+)
+
+const (
+	version     = 3
+	cipherAES   = "aes-128-ctr"
+	prfSHA256   = "hmac-sha256"
+	dkLen       = 32
+	saltLen     = 32
+	keyFileMode = 0o600
+
+	// the scrypt parameters of a keystore file are untrusted, they are limited to 4 times the cost of
+	// StandardScryptOptions: N*r*p <= 2^23, at most 1GB memory (128*N*r bytes)
+	maxScryptN    = 1 << 20
+	maxScryptR    = 32
+	maxScryptP    = 16
+	maxScryptCost = 1 << 23
+)
+
+var (
+	ErrDecrypt         = errors.New("could not decrypt key with given passphrase")
+	ErrUnsupportedKDF  = errors.New("unsupported key derivation function")
+	ErrUnsupportedVer  = errors.New("unsupported keystore version")
+	ErrUnsupportedAlgo = errors.New("unsupported cipher")
+	ErrKDFParams       = errors.New("invalid key derivation parameters")
+)
+
+// EncryptOptions selects the key derivation function and its cost.
+type EncryptOptions struct {
+	KDF string // KDFScrypt or KDFPBKDF2
+
+	ScryptN int
+	ScryptR int
+	ScryptP int
+
+	PBKDF2Iterations int
+}
+
+var (
+	// StandardScryptOptions uses 256MB memory and takes approximately 1s CPU time on a modern processor, same as geth.
+	StandardScryptOptions = EncryptOptions{KDF: KDFScrypt, ScryptN: 1 << 18, ScryptR: 8, ScryptP: 1}
+	// LightScryptOptions uses 4MB memory and takes approximately 100ms CPU time on a modern processor, same as geth.
+	LightScryptOptions = EncryptOptions{KDF: KDFScrypt, ScryptN: 1 << 12, ScryptR: 8, ScryptP: 6}
+	// PBKDF2Options uses 262144 iterations of hmac-sha256, same as the specification test vectors.
+	PBKDF2Options = EncryptOptions{KDF: KDFPBKDF2, PBKDF2Iterations: 1 << 18}
+)
+
+type keyJSON struct {
+	Address string     `json:"address,omitempty"`
+	Crypto  cryptoJSON `json:"crypto"`
+	ID      string     `json:"id"`
+	Version int        `json:"version"`
+}
+
+type cryptoJSON struct {
+	Cipher       string           `json:"cipher"`
+	CipherText   string           `json:"ciphertext"`
+	CipherParams cipherParamsJSON `json:"cipherparams"`
+	KDF          string           `json:"kdf"`
+	KDFParams    map[string]any   `json:"kdfparams"`
+	MAC          string           `json:"mac"`
+}
+
+type cipherParamsJSON struct {
+	IV string `json:"iv"`
+}
+
+// EncryptKey encrypts a raw private key with the passphrase and returns the keystore v3 JSON.
+// StandardScryptOptions is used when no options are given.
+// The address field is set to the Ethereum address if the key is a secp256k1 private key.
+func EncryptKey(privateKey []byte, passphrase string, optsOption ...EncryptOptions) ([]byte, error) {
+	opts := StandardScryptOptions
+	if len(optsOption) > 0 {
+		opts = optsOption[0]
+	}
+
+	salt := make([]byte, saltLen)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, fmt.Errorf("read random salt: %w", err)
+	}
+	iv := make([]byte, aes.BlockSize)
+	if _, err := io.ReadFull(rand.Reader, iv); err != nil {
+		return nil, fmt.Errorf("read random iv: %w", err)
+	}
+
+	var (
+		derivedKey []byte
+		kdfParams  map[string]any
+		err        error
+	)
+	switch opts.KDF {
+	case KDFScrypt:
+		if err := checkScryptParams(opts.ScryptN, opts.ScryptR, opts.ScryptP); err != nil {
+			return nil, err
+		}
+		derivedKey, err = scrypt.Key([]byte(passphrase), salt, opts.ScryptN, opts.ScryptR, opts.ScryptP, dkLen)
+		if err != nil {
+			return nil, fmt.Errorf("scrypt: %w", err)
+		}
+		kdfParams = map[string]any{
+			"n":     opts.ScryptN,
+			"r":     opts.ScryptR,
+			"p":     opts.ScryptP,
+			"dklen": dkLen,
+			"salt":  hex.EncodeToString(salt),
+		}
+	case KDFPBKDF2:
+		if opts.PBKDF2Iterations <= 0 {
+			return nil, errors.New("pbkdf2 iterations must be positive")
+		}
+		derivedKey = pbkdf2.Key([]byte(passphrase), salt, opts.PBKDF2Iterations, dkLen, sha256.New)
+		kdfParams = map[string]any{
+			"c":     opts.PBKDF2Iterations,
+			"prf":   prfSHA256,
+			"dklen": dkLen,
+			"salt":  hex.EncodeToString(salt),
+		}
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedKDF, opts.KDF)
+	}
+
+	cipherText, err := aesCTRXOR(derivedKey[:16], privateKey, iv)
+	if err != nil {
+		return nil, err
+	}
+	mac := crypto.Keccak256(derivedKey[16:32], cipherText)
+
+	id, err := newUUID()
+	if err != nil {
+		return nil, err
+	}
+	kj := keyJSON{
+		Crypto: cryptoJSON{
+			Cipher:       cipherAES,
+			CipherText:   hex.EncodeToString(cipherText),
+			CipherParams: cipherParamsJSON{IV: hex.EncodeToString(iv)},
+			KDF:          opts.KDF,
+			KDFParams:    kdfParams,
+			MAC:          hex.EncodeToString(mac),
+		},
+		ID:      id,
+		Version: version,
+	}
+	if pk, err := crypto.ToECDSA(privateKey); err == nil {
+		kj.Address = hex.EncodeToString(crypto.PubkeyToAddress(pk.PublicKey).Bytes())
+	}
+	return json.Marshal(kj)
+}
+
+// DecryptKey decrypts keystore v3 JSON with the passphrase and returns the raw private key.
+// Both scrypt and pbkdf2 (hmac-sha256) key derivation functions are supported.
+func DecryptKey(keyJSONBytes []byte, passphrase string) ([]byte, error) {
+	var kj keyJSON
+	if err := json.Unmarshal(keyJSONBytes, &kj); err != nil {
+		return nil, fmt.Errorf("unmarshal keystore json: %w", err)
+	}
+	if kj.Version != version {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedVer, kj.Version)
+	}
+	if kj.Crypto.Cipher != cipherAES {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedAlgo, kj.Crypto.Cipher)
+	}
+
+	mac, err := hex.DecodeString(kj.Crypto.MAC)
+	if err != nil {
+		return nil, fmt.Errorf("decode mac: %w", err)
+	}
+	iv, err := hex.DecodeString(kj.Crypto.CipherParams.IV)
+	if err != nil {
+		return nil, fmt.Errorf("decode iv: %w", err)
+	}
+	cipherText, err := hex.DecodeString(kj.Crypto.CipherText)
+	if err != nil {
+		return nil, fmt.Errorf("decode ciphertext: %w", err)
+	}
+
+	derivedKey, err := deriveKey(kj.Crypto, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	calculatedMAC := crypto.Keccak256(derivedKey[16:32], cipherText)
+	if subtle.ConstantTimeCompare(calculatedMAC, mac) != 1 {
+		return nil, ErrDecrypt
+	}
+	return aesCTRXOR(derivedKey[:16], cipherText, iv)
+}
+
+// WriteKeyFile encrypts the private key and writes the keystore v3 JSON to path, readable only by the owner.
+func WriteKeyFile(path string, privateKey []byte, passphrase string, optsOption ...EncryptOptions) error {
+	data, err := EncryptKey(privateKey, passphrase, optsOption...)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("create keystore dir: %w", err)
+	}
+	return os.WriteFile(path, data, keyFileMode)
+}
+
+// ReadKeyFile reads a keystore v3 JSON file and decrypts it with the passphrase.
+func ReadKeyFile(path, passphrase string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read keystore file: %w", err)
+	}
+	return DecryptKey(bytes.TrimSpace(data), passphrase)
+}
+
+func deriveKey(c cryptoJSON, passphrase string) ([]byte, error) {
+	salt, err := hex.DecodeString(getString(c.KDFParams, "salt"))
+	if err != nil {
+		return nil, fmt.Errorf("decode salt: %w", err)
+	}
+	dkLen := getInt(c.KDFParams, "dklen")
+	if dkLen < 32 {
+		return nil, fmt.Errorf("invalid dklen: %d", dkLen)
+	}
+
+	switch strings.ToLower(c.KDF) {
+	case KDFScrypt:
+		n, r, p := getInt(c.KDFParams, "n"), getInt(c.KDFParams, "r"), getInt(c.KDFParams, "p")
+		if err := checkScryptParams(n, r, p); err != nil {
+			return nil, err
+		}
+		key, err := scrypt.Key([]byte(passphrase), salt, n, r, p, dkLen)
+		if err != nil {
+			return nil, fmt.Errorf("scrypt: %w", err)
+		}
+		return key, nil
+	case KDFPBKDF2:
+		if prf := getString(c.KDFParams, "prf"); prf != prfSHA256 {
+			return nil, fmt.Errorf("%w: pbkdf2 prf %q", ErrUnsupportedKDF, prf)
+		}
+		iterations := getInt(c.KDFParams, "c")
+		if iterations <= 0 {
+			return nil, fmt.Errorf("invalid pbkdf2 iterations: %d", iterations)
+		}
+		return pbkdf2.Key([]byte(passphrase), salt, iterations, dkLen, sha256.New), nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedKDF, c.KDF)
+	}
+}
+
+// checkScryptParams rejects the parameters scrypt does not support and the ones costing more than maxScryptCost.
+func checkScryptParams(n, r, p int) error {
+	if n <= 1 || n&(n-1) != 0 || n > maxScryptN {
+		return fmt.Errorf("%w: scrypt n %d must be a power of 2 between 2 and %d", ErrKDFParams, n, maxScryptN)
+	}
+	if r < 1 || r > maxScryptR {
+		return fmt.Errorf("%w: scrypt r %d must be between 1 and %d", ErrKDFParams, r, maxScryptR)
+	}
+	if p < 1 || p > maxScryptP {
+		return fmt.Errorf("%w: scrypt p %d must be between 1 and %d", ErrKDFParams, p, maxScryptP)
+	}
+	if n*r*p > maxScryptCost {
+		return fmt.Errorf("%w: scrypt n*r*p %d exceeds %d", ErrKDFParams, n*r*p, maxScryptCost)
+	}
+	return nil
+}
+
+func aesCTRXOR(key, in, iv []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("new aes cipher: %w", err)
+	}
+	if len(iv) != aes.BlockSize {
+		return nil, fmt.Errorf("invalid iv length: %d", len(iv))
+	}
+	out := make([]byte, len(in))
+	cipher.NewCTR(block, iv).XORKeyStream(out, in)
+	return out, nil
+}
+
+func getInt(params map[string]any, key string) int {
+	// encoding/json decodes numbers into float64
+	if v, ok := params[key].(float64); ok {
+		return int(v)
+	}
+	if v, ok := params[key].(int); ok {
+		return v
+	}
+	return 0
+}
+
+func getString(params map[string]any, key string) string {
+	v, _ := params[key].(string)
+	return v
+}
+
+// newUUID returns a random (version 4) UUID.
+func newUUID() (string, error) {
+	var u [16]byte
+	if _, err := io.ReadFull(rand.Reader, u[:]); err != nil {
+		return "", fmt.Errorf("read random uuid: %w", err)
+	}
+	u[6] = (u[6] & 0x0f) | 0x40 // version 4
+	u[8] = (u[8] & 0x3f) | 0x80 // variant 10
+	return fmt.Sprintf("%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:16]), nil
+}
@@ -0,0 +1,132 @@
+package keystore
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+// https://ethereum.org/developers/docs/data-structures-and-encoding/web3-secret-storage#test-vectors
+func TestDecryptKeyVectors(t *testing.T) {
+	const (
+		passphrase = "testpassword"
+		privateKey = "7a28b5ba57c53603b0b07b56bba752f7784bf506fa95edc395f5cf6c7514fe9d"
+	)
+	for _, tc := range []struct {
+		name    string
+		keyJSON string
+	}{
+		{
+			"pbkdf2",
+			`{"crypto":{"cipher":"aes-128-ctr","cipherparams":{"iv":"6087dab2f9fdbbfaddc31a909735c1e6"},"ciphertext":"5318b4d5bcd28de64ee5559e671353e16f075ecae9f99c7a79a38af5f869aa46","kdf":"pbkdf2","kdfparams":{"c":262144,"dklen":32,"prf":"hmac-sha256","salt":"ae3cd4e7013836a3df6bd7241b12db061dbe2c6785853cce422d148a624ce0bd"},"mac":"517ead924a9d0dc3124507e3393d175ce3ff7c1e96529c6c555ce9e51205e9b2"},"id":"3198bc9c-6672-5ab3-d995-4942343ae5b6","version":3}`,
+		},
+		{
+			"scrypt",
+			`{"crypto":{"cipher":"aes-128-ctr","cipherparams":{"iv":"83dbcc02d8ccb40e466191a123791e0e"},"ciphertext":"d172bf743a674da9cdad04534d56926ef8358534d458fffccd4e6ad2fbde479c","kdf":"scrypt","kdfparams":{"dklen":32,"n":262144,"p":8,"r":1,"salt":"ab0c7876052600dd703518d6fc3fe8984592145b591fc8fb5c6d43190334ba19"},"mac":"2103ac29920d71da29f15d75b4a16dbe95cfd7ff8faea1056c33131d846e3097"},"id":"3198bc9c-6672-5ab3-d995-4942343ae5b6","version":3}`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			key, err := DecryptKey([]byte(tc.keyJSON), passphrase)
+			assert.NoError(t, err)
+			assert.Equal(t, privateKey, hex.EncodeToString(key))
+
+			_, err = DecryptKey([]byte(tc.keyJSON), "wrongpassword")
+			assert.ErrorIs(t, err, ErrDecrypt)
+		})
+	}
+}
+
+func TestDecryptKeyScryptLimits(t *testing.T) {
+	const keyJSON = `{"crypto":{"cipher":"aes-128-ctr","cipherparams":{"iv":"83dbcc02d8ccb40e466191a123791e0e"},"ciphertext":"d172bf743a674da9cdad04534d56926ef8358534d458fffccd4e6ad2fbde479c","kdf":"scrypt","kdfparams":{"dklen":32,"n":%d,"p":%d,"r":%d,"salt":"ab0c7876052600dd703518d6fc3fe8984592145b591fc8fb5c6d43190334ba19"},"mac":"2103ac29920d71da29f15d75b4a16dbe95cfd7ff8faea1056c33131d846e3097"},"id":"3198bc9c-6672-5ab3-d995-4942343ae5b6","version":3}`
+	for _, params := range [][3]int{
+		{1 << 30, 1, 1}, // n too large
+		{262143, 8, 1},  // n not a power of 2
+		{0, 8, 1},
+		{1 << 18, 1 << 20, 1}, // r too large
+		{1 << 18, 8, 1 << 20}, // p too large
+		{1 << 20, 32, 1},      // 4GB memory
+		{1 << 18, 8, 16},      // cpu cost
+	} {
+		_, err := DecryptKey(fmt.Appendf(nil, keyJSON, params[0], params[2], params[1]), "testpassword")
+		assert.ErrorIs(t, err, ErrKDFParams, "n=%d r=%d p=%d", params[0], params[1], params[2])
+	}
+
+	_, err := EncryptKey(make([]byte, 32), "", EncryptOptions{KDF: KDFScrypt, ScryptN: 1 << 22, ScryptR: 8, ScryptP: 1})
+	assert.ErrorIs(t, err, ErrKDFParams)
+}
+
+func TestEncryptKey(t *testing.T) {
+	pk, err := crypto.GenerateKey()
+	assert.NoError(t, err)
+	privateKey := crypto.FromECDSA(pk)
+	passphrase := "p@ssw0rd"
+
+	for _, opts := range []EncryptOptions{
+		LightScryptOptions,
+		{KDF: KDFPBKDF2, PBKDF2Iterations: 1024},
+	} {
+		t.Run(opts.KDF, func(t *testing.T) {
+			keyJSON, err := EncryptKey(privateKey, passphrase, opts)
+			assert.NoError(t, err)
+
+			key, err := DecryptKey(keyJSON, passphrase)
+			assert.NoError(t, err)
+			assert.Equal(t, privateKey, key)
+
+			_, err = DecryptKey(keyJSON, "")
+			assert.ErrorIs(t, err, ErrDecrypt)
+
+			// compatible with geth
+			gethKey, err := keystore.DecryptKey(keyJSON, passphrase)
+			assert.NoError(t, err)
+			assert.Equal(t, crypto.PubkeyToAddress(pk.PublicKey), gethKey.Address)
+		})
+	}
+
+	t.Run("unsupported kdf", func(t *testing.T) {
+		_, err := EncryptKey(privateKey, passphrase, EncryptOptions{KDF: "argon2"})
+		assert.ErrorIs(t, err, ErrUnsupportedKDF)
+	})
+
+	t.Run("ed25519 key", func(t *testing.T) {
+		_, edKey, err := ed25519.GenerateKey(nil)
+		assert.NoError(t, err)
+		keyJSON, err := EncryptKey(edKey, passphrase, LightScryptOptions)
+		assert.NoError(t, err)
+		key, err := DecryptKey(keyJSON, passphrase)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte(edKey), key)
+	})
+}
+
+func TestKeyFile(t *testing.T) {
+	pk, err := crypto.GenerateKey()
+	assert.NoError(t, err)
+	privateKey := crypto.FromECDSA(pk)
+
+	// geth keystore files can be read
+	gethKeyJSON, err := keystore.EncryptKey(&keystore.Key{
+		Address:    crypto.PubkeyToAddress(pk.PublicKey),
+		PrivateKey: pk,
+	}, "geth", keystore.LightScryptN, keystore.LightScryptP)
+	assert.NoError(t, err)
+	key, err := DecryptKey(gethKeyJSON, "geth")
+	assert.NoError(t, err)
+	assert.Equal(t, privateKey, key)
+
+	path := filepath.Join(t.TempDir(), "keys", "acc1.json")
+	err = WriteKeyFile(path, privateKey, "file", LightScryptOptions)
+	assert.NoError(t, err)
+	key, err = ReadKeyFile(path, "file")
+	assert.NoError(t, err)
+	assert.Equal(t, privateKey, key)
+
+	_, err = ReadKeyFile(filepath.Join(t.TempDir(), "missing.json"), "file")
+	assert.Error(t, err)
+}
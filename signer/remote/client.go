@@ -0,0 +1,144 @@
+package remote
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/15ho/wallet-utils-go/signer"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"google.golang.org/grpc"
+)
+
+// Client connects to a remote signing service, its signers can be passed to the NewWalletClientFromSigner constructors.
+type Client struct {
+	conn *grpc.ClientConn
+}
+
+// NewClient creates a client of the signing service at target, e.g. "127.0.0.1:50051".
+// The transport credentials must be given in opts, e.g. grpc.WithTransportCredentials(insecure.NewCredentials()) for localhost.
+func NewClient(target string, opts ...grpc.DialOption) (*Client, error) {
+	conn, err := grpc.NewClient(target, append(opts, grpc.WithDefaultCallOptions(grpc.CallContentSubtype(codecName)))...)
+	if err != nil {
+		return nil, fmt.Errorf("new grpc client: %w", err)
+	}
+	return &Client{conn: conn}, nil
+}
+
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+func (c *Client) GetPublicKey(ctx context.Context, keyID string) (*GetPublicKeyResponse, error) {
+	resp := new(GetPublicKeyResponse)
+	if err := c.conn.Invoke(ctx, fullMethod("GetPublicKey"), &GetPublicKeyRequest{KeyID: keyID}, resp); err != nil {
+		return nil, fmt.Errorf("get public key: %w", err)
+	}
+	return resp, nil
+}
+
+// Secp256k1Signer returns the signer of a secp256k1 key held by the server, for Ethereum, Arbitrum and Tron wallets.
+func (c *Client) Secp256k1Signer(ctx context.Context, keyID string) (*Secp256k1Signer, error) {
+	resp, err := c.GetPublicKey(ctx, keyID)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Curve != CurveSecp256k1 {
+		return nil, fmt.Errorf("key %q is a %s key", keyID, resp.Curve)
+	}
+	publicKey, err := crypto.DecompressPubkey(resp.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key: %w", err)
+	}
+	return &Secp256k1Signer{c: c, keyID: keyID, publicKey: publicKey}, nil
+}
+
+// Ed25519Signer returns the signer of an ed25519 key held by the server, for Solana wallets.
+func (c *Client) Ed25519Signer(ctx context.Context, keyID string) (*Ed25519Signer, error) {
+	resp, err := c.GetPublicKey(ctx, keyID)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Curve != CurveEd25519 {
+		return nil, fmt.Errorf("key %q is a %s key", keyID, resp.Curve)
+	}
+	if len(resp.PublicKey) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid public key length: %d", len(resp.PublicKey))
+	}
+	return &Ed25519Signer{c: c, keyID: keyID, publicKey: resp.PublicKey}, nil
+}
+
+// ErrDigestSigning is returned by Secp256k1Signer.SignDigest, the server only signs whole transactions.
+var ErrDigestSigning = errors.New("remote signer does not sign digests")
+
+// Secp256k1Signer signs Ethereum/Arbitrum transactions with SignEVMTx, and Tron transactions with SignTronTx.
+type Secp256k1Signer struct {
+	c         *Client
+	keyID     string
+	publicKey *ecdsa.PublicKey
+}
+
+var (
+	_ signer.Secp256k1Signer = (*Secp256k1Signer)(nil)
+	_ signer.EVMTxSigner     = (*Secp256k1Signer)(nil)
+	_ signer.TronTxSigner    = (*Secp256k1Signer)(nil)
+)
+
+func (s *Secp256k1Signer) PublicKey() *ecdsa.PublicKey {
+	return s.publicKey
+}
+
+// SignDigest always fails with ErrDigestSigning, the wallets sign with SignEVMTx and SignTronTx.
+func (s *Secp256k1Signer) SignDigest(context.Context, []byte) ([]byte, error) {
+	return nil, ErrDigestSigning
+}
+
+// SignTronTx sends the raw data of a Tron transaction, the server signs its sha256 (txid).
+func (s *Secp256k1Signer) SignTronTx(ctx context.Context, rawData []byte) ([]byte, error) {
+	resp := new(SignatureResponse)
+	if err := s.c.conn.Invoke(ctx, fullMethod("SignTronTx"), &SignTronTxRequest{KeyID: s.keyID, RawData: rawData}, resp); err != nil {
+		return nil, fmt.Errorf("sign tron tx: %w", err)
+	}
+	return resp.Signature, nil
+}
+
+func (s *Secp256k1Signer) SignEVMTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	txBytes, err := tx.MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("marshal tx: %w", err)
+	}
+	resp := new(SignEVMTxResponse)
+	if err := s.c.conn.Invoke(ctx, fullMethod("SignEVMTx"), &SignEVMTxRequest{KeyID: s.keyID, ChainID: chainID.String(), Tx: txBytes}, resp); err != nil {
+		return nil, fmt.Errorf("sign evm tx: %w", err)
+	}
+	signedTx := new(types.Transaction)
+	if err := signedTx.UnmarshalBinary(resp.SignedTx); err != nil {
+		return nil, fmt.Errorf("unmarshal signed tx: %w", err)
+	}
+	return signedTx, nil
+}
+
+// Ed25519Signer signs Solana transaction messages with SignSolanaMessage.
+type Ed25519Signer struct {
+	c         *Client
+	keyID     string
+	publicKey ed25519.PublicKey
+}
+
+var _ signer.Ed25519Signer = (*Ed25519Signer)(nil)
+
+func (s *Ed25519Signer) PublicKey() ed25519.PublicKey {
+	return s.publicKey
+}
+
+func (s *Ed25519Signer) SignMessage(ctx context.Context, message []byte) ([]byte, error) {
+	resp := new(SignatureResponse)
+	if err := s.c.conn.Invoke(ctx, fullMethod("SignSolanaMessage"), &SignSolanaMessageRequest{KeyID: s.keyID, Message: message}, resp); err != nil {
+		return nil, fmt.Errorf("sign solana message: %w", err)
+	}
+	return resp.Signature, nil
+}
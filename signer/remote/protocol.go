@@ -0,0 +1,144 @@
+package remote
+
+import (
+	"context"
+	"encoding/json"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding"
+)
+
+// The signing protocol is a plain gRPC service whose messages are encoded as JSON,
+// so neither protoc nor generated code is needed. Byte fields are base64 encoded by encoding/json.
+//
+//	service RemoteSigner {
+//	  rpc GetPublicKey(GetPublicKeyRequest) returns (GetPublicKeyResponse);
+//	  rpc SignEVMTx(SignEVMTxRequest) returns (SignEVMTxResponse);
+//	  rpc SignTronTx(SignTronTxRequest) returns (SignatureResponse);
+//	  rpc SignSolanaMessage(SignSolanaMessageRequest) returns (SignatureResponse);
+//	}
+
+const (
+	serviceName = "walletutils.signer.v1.RemoteSigner"
+	codecName   = "json"
+)
+
+// Curve of a key held by the server.
+const (
+	CurveSecp256k1 = "secp256k1"
+	CurveEd25519   = "ed25519"
+)
+
+type GetPublicKeyRequest struct {
+	KeyID string `json:"key_id"`
+}
+
+type GetPublicKeyResponse struct {
+	Curve     string `json:"curve"`
+	PublicKey []byte `json:"public_key"` // 33 bytes compressed secp256k1 or 32 bytes ed25519 public key
+}
+
+type SignEVMTxRequest struct {
+	KeyID   string `json:"key_id"`
+	ChainID string `json:"chain_id"` // decimal
+	Tx      []byte `json:"tx"`       // unsigned transaction, binary encoded by types.Transaction.MarshalBinary
+}
+
+type SignEVMTxResponse struct {
+	SignedTx []byte `json:"signed_tx"` // binary encoded signed transaction
+}
+
+type SignTronTxRequest struct {
+	KeyID   string `json:"key_id"`
+	RawData []byte `json:"raw_data"` // protobuf encoded transaction raw data, the server signs its sha256 (txid)
+}
+
+type SignSolanaMessageRequest struct {
+	KeyID   string `json:"key_id"`
+	Message []byte `json:"message"` // serialized transaction message
+}
+
+type SignatureResponse struct {
+	Signature []byte `json:"signature"`
+}
+
+// RemoteSignerServer is the server API of the signing protocol, implemented by Server.
+type RemoteSignerServer interface {
+	GetPublicKey(context.Context, *GetPublicKeyRequest) (*GetPublicKeyResponse, error)
+	SignEVMTx(context.Context, *SignEVMTxRequest) (*SignEVMTxResponse, error)
+	SignTronTx(context.Context, *SignTronTxRequest) (*SignatureResponse, error)
+	SignSolanaMessage(context.Context, *SignSolanaMessageRequest) (*SignatureResponse, error)
+}
+
+// RegisterRemoteSignerServer registers the signing service on a gRPC server.
+func RegisterRemoteSignerServer(s grpc.ServiceRegistrar, srv RemoteSignerServer) {
+	s.RegisterService(&serviceDesc, srv)
+}
+
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: serviceName,
+	HandlerType: (*RemoteSignerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetPublicKey",
+			Handler:    unaryHandler(RemoteSignerServer.GetPublicKey),
+		},
+		{
+			MethodName: "SignEVMTx",
+			Handler:    unaryHandler(RemoteSignerServer.SignEVMTx),
+		},
+		{
+			MethodName: "SignTronTx",
+			Handler:    unaryHandler(RemoteSignerServer.SignTronTx),
+		},
+		{
+			MethodName: "SignSolanaMessage",
+			Handler:    unaryHandler(RemoteSignerServer.SignSolanaMessage),
+		},
+	},
+	Metadata: "signer/remote/protocol.go",
+}
+
+// unaryHandler adapts a method of RemoteSignerServer to a grpc.MethodHandler, same as the generated code of protoc-gen-go-grpc.
+func unaryHandler[Req, Resp any](method func(RemoteSignerServer, context.Context, *Req) (*Resp, error)) grpc.MethodHandler {
+	return func(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+		in := new(Req)
+		if err := dec(in); err != nil {
+			return nil, err
+		}
+		if interceptor == nil {
+			return method(srv.(RemoteSignerServer), ctx, in)
+		}
+		fullMethod, _ := grpc.Method(ctx)
+		info := &grpc.UnaryServerInfo{
+			Server:     srv,
+			FullMethod: fullMethod,
+		}
+		return interceptor(ctx, in, info, func(ctx context.Context, req any) (any, error) {
+			return method(srv.(RemoteSignerServer), ctx, req.(*Req))
+		})
+	}
+}
+
+func fullMethod(method string) string {
+	return "/" + serviceName + "/" + method
+}
+
+func init() {
+	encoding.RegisterCodec(jsonCodec{})
+}
+
+// jsonCodec is selected by the client with grpc.CallContentSubtype(codecName).
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+func (jsonCodec) Name() string {
+	return codecName
+}
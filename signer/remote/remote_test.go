@@ -0,0 +1,113 @@
+package remote
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"math/big"
+	"net"
+	"path/filepath"
+	"testing"
+
+	"github.com/15ho/wallet-utils-go/keystore"
+	"github.com/15ho/wallet-utils-go/signer"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+func TestRemoteSigner(t *testing.T) {
+	ctx := context.Background()
+
+	// keystore files of the server
+	dir := t.TempDir()
+	ethKey, err := crypto.GenerateKey()
+	assert.NoError(t, err)
+	err = keystore.WriteKeyFile(filepath.Join(dir, "eth.json"), crypto.FromECDSA(ethKey), "p@ssw0rd", keystore.LightScryptOptions)
+	assert.NoError(t, err)
+	solPub, solKey, err := ed25519.GenerateKey(nil)
+	assert.NoError(t, err)
+	err = keystore.WriteKeyFile(filepath.Join(dir, "sol.json"), solKey, "p@ssw0rd", keystore.LightScryptOptions)
+	assert.NoError(t, err)
+
+	srv := NewServer()
+	keyIDs, err := srv.LoadKeystoreDir(dir, "p@ssw0rd")
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"eth", "sol"}, keyIDs)
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	gs := grpc.NewServer()
+	RegisterRemoteSignerServer(gs, srv)
+	go func() {
+		_ = gs.Serve(lis)
+	}()
+	defer gs.Stop()
+
+	cli, err := NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.NoError(t, err)
+	defer cli.Close()
+
+	t.Run("evm tx", func(t *testing.T) {
+		s, err := cli.Secp256k1Signer(ctx, "eth")
+		assert.NoError(t, err)
+		assert.Equal(t, crypto.PubkeyToAddress(ethKey.PublicKey), crypto.PubkeyToAddress(*s.PublicKey()))
+
+		chainID := big.NewInt(42161)
+		to := common.HexToAddress("0x9858EfFD232B4033E47d90003D41EC34EcaEda94")
+		for _, tx := range []*types.Transaction{
+			types.NewTransaction(1, to, big.NewInt(1), 21000, big.NewInt(1e9), nil),
+			types.NewTx(&types.DynamicFeeTx{ChainID: chainID, Nonce: 2, Gas: 21000, GasFeeCap: big.NewInt(2e9), GasTipCap: big.NewInt(1e9), To: &to, Value: big.NewInt(1)}),
+		} {
+			txSigner := types.LatestSignerForChainID(chainID)
+			signedTx, err := signer.SignEVMTx(ctx, s, tx, txSigner)
+			assert.NoError(t, err)
+			expectedTx, err := types.SignTx(tx, txSigner, ethKey)
+			assert.NoError(t, err)
+			assert.Equal(t, expectedTx.Hash(), signedTx.Hash())
+		}
+	})
+
+	t.Run("tron tx", func(t *testing.T) {
+		s, err := cli.Secp256k1Signer(ctx, "eth")
+		assert.NoError(t, err)
+		rawData := []byte("raw data")
+		signature, err := s.SignTronTx(ctx, rawData)
+		assert.NoError(t, err)
+		txid := sha256.Sum256(rawData)
+		expected, err := crypto.Sign(txid[:], ethKey)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, signature)
+
+		_, err = s.SignTronTx(ctx, nil)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+		// no blind signing of digests, e.g. the signing hash of an Ethereum transaction
+		_, err = s.SignDigest(ctx, txid[:])
+		assert.ErrorIs(t, err, ErrDigestSigning)
+	})
+
+	t.Run("solana message", func(t *testing.T) {
+		s, err := cli.Ed25519Signer(ctx, "sol")
+		assert.NoError(t, err)
+		assert.Equal(t, solPub, s.PublicKey())
+		message := []byte("solana message")
+		signature, err := s.SignMessage(ctx, message)
+		assert.NoError(t, err)
+		assert.True(t, ed25519.Verify(solPub, message, signature))
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		_, err := cli.Secp256k1Signer(ctx, "btc")
+		assert.Equal(t, codes.NotFound, status.Code(err))
+		_, err = cli.Ed25519Signer(ctx, "eth")
+		assert.Error(t, err)
+		_, err = cli.Secp256k1Signer(ctx, "sol")
+		assert.Error(t, err)
+	})
+}
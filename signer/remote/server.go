@@ -0,0 +1,188 @@
+package remote
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/15ho/wallet-utils-go/internal/zlog"
+	"github.com/15ho/wallet-utils-go/keystore"
+	"github.com/15ho/wallet-utils-go/signer"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Server is the reference RemoteSignerServer, the keys are loaded from keystore files and kept in memory.
+// It is meant to run as a separate process, e.g. on localhost next to the wallets.
+type Server struct {
+	mu            sync.RWMutex
+	secp256k1Keys map[string]*signer.LocalSecp256k1Signer
+	ed25519Keys   map[string]*signer.LocalEd25519Signer
+}
+
+var _ RemoteSignerServer = (*Server)(nil)
+
+func NewServer() *Server {
+	return &Server{
+		secp256k1Keys: make(map[string]*signer.LocalSecp256k1Signer),
+		ed25519Keys:   make(map[string]*signer.LocalEd25519Signer),
+	}
+}
+
+// AddKey adds a raw private key: 32 bytes secp256k1 keys (Ethereum, Arbitrum, Tron)
+// or 64 bytes ed25519 keys (Solana).
+func (s *Server) AddKey(keyID string, privateKey []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.secp256k1Keys[keyID]; ok {
+		return fmt.Errorf("duplicate key id %q", keyID)
+	}
+	if _, ok := s.ed25519Keys[keyID]; ok {
+		return fmt.Errorf("duplicate key id %q", keyID)
+	}
+
+	switch len(privateKey) {
+	case 32:
+		pk, err := crypto.ToECDSA(privateKey)
+		if err != nil {
+			return fmt.Errorf("invalid secp256k1 private key: %w", err)
+		}
+		s.secp256k1Keys[keyID] = signer.NewLocalSecp256k1Signer(pk)
+	case ed25519.PrivateKeySize:
+		ls, err := signer.NewLocalEd25519Signer(ed25519.PrivateKey(privateKey))
+		if err != nil {
+			return err
+		}
+		s.ed25519Keys[keyID] = ls
+	default:
+		return fmt.Errorf("invalid private key length: %d", len(privateKey))
+	}
+	return nil
+}
+
+// LoadKeystoreFile decrypts a keystore v3 JSON file and adds its key, see keystore.WriteKeyFile.
+func (s *Server) LoadKeystoreFile(keyID, path, passphrase string) error {
+	privateKey, err := keystore.ReadKeyFile(path, passphrase)
+	if err != nil {
+		return fmt.Errorf("open keystore %s: %w", path, err)
+	}
+	return s.AddKey(keyID, privateKey)
+}
+
+// LoadKeystoreDir loads all *.json keystore files of dir with the same passphrase,
+// the key id is the file name without the .json extension.
+func (s *Server) LoadKeystoreDir(dir, passphrase string) (keyIDs []string, err error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return
+	}
+	if len(paths) == 0 {
+		err = fmt.Errorf("no keystore files in %s: %w", dir, os.ErrNotExist)
+		return
+	}
+	for _, path := range paths {
+		keyID := strings.TrimSuffix(filepath.Base(path), ".json")
+		if err = s.LoadKeystoreFile(keyID, path, passphrase); err != nil {
+			return
+		}
+		keyIDs = append(keyIDs, keyID)
+	}
+	return
+}
+
+func (s *Server) GetPublicKey(_ context.Context, req *GetPublicKeyRequest) (*GetPublicKeyResponse, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if ls, ok := s.secp256k1Keys[req.KeyID]; ok {
+		return &GetPublicKeyResponse{
+			Curve:     CurveSecp256k1,
+			PublicKey: crypto.CompressPubkey(ls.PublicKey()),
+		}, nil
+	}
+	if ls, ok := s.ed25519Keys[req.KeyID]; ok {
+		return &GetPublicKeyResponse{
+			Curve:     CurveEd25519,
+			PublicKey: ls.PublicKey(),
+		}, nil
+	}
+	return nil, status.Errorf(codes.NotFound, "key %q not found", req.KeyID)
+}
+
+func (s *Server) SignEVMTx(ctx context.Context, req *SignEVMTxRequest) (*SignEVMTxResponse, error) {
+	ls, err := s.secp256k1Key(req.KeyID)
+	if err != nil {
+		return nil, err
+	}
+	chainID, ok := new(big.Int).SetString(req.ChainID, 10)
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid chain id %q", req.ChainID)
+	}
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(req.Tx); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid tx: %v", err)
+	}
+	signedTx, err := signer.SignEVMTx(ctx, ls, tx, types.LatestSignerForChainID(chainID))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "sign tx: %v", err)
+	}
+	signedTxBytes, err := signedTx.MarshalBinary()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "marshal tx: %v", err)
+	}
+	zlog.Info("signed evm tx", zap.String("keyID", req.KeyID), zap.String("chainID", req.ChainID), zap.String("txHash", signedTx.Hash().Hex()))
+	return &SignEVMTxResponse{SignedTx: signedTxBytes}, nil
+}
+
+// SignTronTx signs the txid computed from the raw data, so the server never signs a digest chosen by the caller,
+// e.g. the signing hash of an Ethereum transaction that would bypass the checks of SignEVMTx.
+func (s *Server) SignTronTx(ctx context.Context, req *SignTronTxRequest) (*SignatureResponse, error) {
+	ls, err := s.secp256k1Key(req.KeyID)
+	if err != nil {
+		return nil, err
+	}
+	if len(req.RawData) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "empty tx raw data")
+	}
+	txID := sha256.Sum256(req.RawData)
+	signature, err := ls.SignDigest(ctx, txID[:])
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "sign tx: %v", err)
+	}
+	zlog.Info("signed tron tx", zap.String("keyID", req.KeyID), zap.String("txid", hex.EncodeToString(txID[:])))
+	return &SignatureResponse{Signature: signature}, nil
+}
+
+func (s *Server) SignSolanaMessage(ctx context.Context, req *SignSolanaMessageRequest) (*SignatureResponse, error) {
+	s.mu.RLock()
+	ls, ok := s.ed25519Keys[req.KeyID]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "ed25519 key %q not found", req.KeyID)
+	}
+	signature, err := ls.SignMessage(ctx, req.Message)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "sign message: %v", err)
+	}
+	zlog.Info("signed solana message", zap.String("keyID", req.KeyID))
+	return &SignatureResponse{Signature: signature}, nil
+}
+
+func (s *Server) secp256k1Key(keyID string) (*signer.LocalSecp256k1Signer, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	ls, ok := s.secp256k1Keys[keyID]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "secp256k1 key %q not found", keyID)
+	}
+	return ls, nil
+}
@@ -0,0 +1,136 @@
+package signer
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// A Signer holds the private key of one account and signs on behalf of the WalletClients,
+// so the key can live in memory, a KMS, an HSM or a remote signing service.
+
+var (
+	ErrSignerMismatch = errors.New("signature does not match the signer public key")
+	ErrTxModified     = errors.New("signed transaction differs from the unsigned transaction")
+)
+
+// Secp256k1Signer signs 32 bytes digests, used by Ethereum, Arbitrum and Tron.
+type Secp256k1Signer interface {
+	PublicKey() *ecdsa.PublicKey
+	// SignDigest returns the 65 bytes recoverable signature [R || S || V] of the digest, V is 0 or 1.
+	SignDigest(ctx context.Context, digest []byte) ([]byte, error)
+}
+
+// Ed25519Signer signs messages, used by Solana.
+type Ed25519Signer interface {
+	PublicKey() ed25519.PublicKey
+	SignMessage(ctx context.Context, message []byte) ([]byte, error)
+}
+
+// EVMTxSigner is implemented by the Secp256k1Signers that sign whole Ethereum/Arbitrum transactions instead of digests,
+// e.g. a remote signing service that inspects the transaction before signing it.
+type EVMTxSigner interface {
+	SignEVMTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+}
+
+// TronTxSigner is implemented by the Secp256k1Signers that sign the raw data of Tron transactions instead of digests,
+// e.g. a remote signing service that computes the transaction id itself.
+type TronTxSigner interface {
+	// SignTronTx returns the 65 bytes recoverable signature of sha256(rawData), rawData is the protobuf encoded TransactionRaw.
+	SignTronTx(ctx context.Context, rawData []byte) ([]byte, error)
+}
+
+// SignEVMTx signs an Ethereum/Arbitrum transaction with the Secp256k1Signer, same as types.SignTx.
+// The sender of the signed transaction is checked against the public key of the signer.
+func SignEVMTx(ctx context.Context, s Secp256k1Signer, tx *types.Transaction, txSigner types.Signer) (signedTx *types.Transaction, err error) {
+	if ts, ok := s.(EVMTxSigner); ok {
+		signedTx, err = ts.SignEVMTx(ctx, tx, txSigner.ChainID())
+		if err != nil {
+			return nil, err
+		}
+		// the signing hash does not cover the signature, it must be unchanged
+		if txSigner.Hash(signedTx) != txSigner.Hash(tx) {
+			return nil, ErrTxModified
+		}
+	} else {
+		signature, err := s.SignDigest(ctx, txSigner.Hash(tx).Bytes())
+		if err != nil {
+			return nil, err
+		}
+		signedTx, err = tx.WithSignature(txSigner, signature)
+		if err != nil {
+			return nil, err
+		}
+	}
+	sender, err := types.Sender(txSigner, signedTx)
+	if err != nil {
+		return nil, err
+	}
+	if sender != crypto.PubkeyToAddress(*s.PublicKey()) {
+		return nil, ErrSignerMismatch
+	}
+	return signedTx, nil
+}
+
+// LocalSecp256k1Signer keeps the private key in memory.
+type LocalSecp256k1Signer struct {
+	privateKey *ecdsa.PrivateKey
+}
+
+var _ Secp256k1Signer = (*LocalSecp256k1Signer)(nil)
+
+func NewLocalSecp256k1Signer(privateKey *ecdsa.PrivateKey) *LocalSecp256k1Signer {
+	return &LocalSecp256k1Signer{privateKey: privateKey}
+}
+
+// NewLocalSecp256k1SignerFromHex accepts the private key hex with or without 0x prefix.
+func NewLocalSecp256k1SignerFromHex(privateKeyHex string) (*LocalSecp256k1Signer, error) {
+	return NewLocalSecp256k1SignerFromBytes(common.FromHex(privateKeyHex))
+}
+
+// NewLocalSecp256k1SignerFromBytes accepts the 32 bytes raw private key, e.g. decrypted by keystore.ReadKeyFile.
+func NewLocalSecp256k1SignerFromBytes(privateKey []byte) (*LocalSecp256k1Signer, error) {
+	pk, err := crypto.ToECDSA(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %w", err)
+	}
+	return NewLocalSecp256k1Signer(pk), nil
+}
+
+func (s *LocalSecp256k1Signer) PublicKey() *ecdsa.PublicKey {
+	return &s.privateKey.PublicKey
+}
+
+func (s *LocalSecp256k1Signer) SignDigest(_ context.Context, digest []byte) ([]byte, error) {
+	return crypto.Sign(digest, s.privateKey)
+}
+
+// LocalEd25519Signer keeps the private key in memory.
+type LocalEd25519Signer struct {
+	privateKey ed25519.PrivateKey
+}
+
+var _ Ed25519Signer = (*LocalEd25519Signer)(nil)
+
+// NewLocalEd25519Signer accepts the 64 bytes private key (seed || public key).
+func NewLocalEd25519Signer(privateKey ed25519.PrivateKey) (*LocalEd25519Signer, error) {
+	if len(privateKey) != ed25519.PrivateKeySize {
+		return nil, fmt.Errorf("invalid private key length: %d", len(privateKey))
+	}
+	return &LocalEd25519Signer{privateKey: privateKey}, nil
+}
+
+func (s *LocalEd25519Signer) PublicKey() ed25519.PublicKey {
+	return s.privateKey.Public().(ed25519.PublicKey)
+}
+
+func (s *LocalEd25519Signer) SignMessage(_ context.Context, message []byte) ([]byte, error) {
+	return ed25519.Sign(s.privateKey, message), nil
+}
@@ -0,0 +1,91 @@
+package signer
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+// wrongKeySigner reports the public key of one key but signs with another.
+type wrongKeySigner struct {
+	*LocalSecp256k1Signer
+	publicKey *ecdsa.PublicKey
+}
+
+func (s wrongKeySigner) PublicKey() *ecdsa.PublicKey {
+	return s.publicKey
+}
+
+func TestSignEVMTx(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewLocalSecp256k1SignerFromHex("0x4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318")
+	assert.NoError(t, err)
+	account := crypto.PubkeyToAddress(*s.PublicKey())
+	assert.Equal(t, "0x2c7536E3605D9C16a7a3D7b1898e529396a65c23", account.Hex())
+
+	fromBytes, err := NewLocalSecp256k1SignerFromBytes(common.FromHex("0x4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318"))
+	assert.NoError(t, err)
+	assert.Equal(t, s.PublicKey(), fromBytes.PublicKey())
+	_, err = NewLocalSecp256k1SignerFromBytes(make([]byte, 31))
+	assert.Error(t, err)
+
+	chainID := big.NewInt(1)
+	to := common.HexToAddress("0x9858EfFD232B4033E47d90003D41EC34EcaEda94")
+	for _, tc := range []struct {
+		name     string
+		tx       *types.Transaction
+		txSigner types.Signer
+	}{
+		{"legacy", types.NewTransaction(0, to, big.NewInt(1), 21000, big.NewInt(1e9), nil), types.NewEIP155Signer(chainID)},
+		{"dynamic fee", types.NewTx(&types.DynamicFeeTx{
+			ChainID:   chainID,
+			Gas:       21000,
+			GasFeeCap: big.NewInt(2e9),
+			GasTipCap: big.NewInt(1e9),
+			To:        &to,
+			Value:     big.NewInt(1),
+		}), types.NewLondonSigner(chainID)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			signedTx, err := SignEVMTx(ctx, s, tc.tx, tc.txSigner)
+			assert.NoError(t, err)
+
+			// same as signing with the private key directly
+			expectedTx, err := types.SignTx(tc.tx, tc.txSigner, s.privateKey)
+			assert.NoError(t, err)
+			assert.Equal(t, expectedTx.Hash(), signedTx.Hash())
+
+			sender, err := types.Sender(tc.txSigner, signedTx)
+			assert.NoError(t, err)
+			assert.Equal(t, account, sender)
+		})
+	}
+
+	otherKey, err := crypto.GenerateKey()
+	assert.NoError(t, err)
+	_, err = SignEVMTx(ctx, wrongKeySigner{s, &otherKey.PublicKey}, types.NewTransaction(0, to, big.NewInt(1), 21000, big.NewInt(1e9), nil), types.NewEIP155Signer(chainID))
+	assert.ErrorIs(t, err, ErrSignerMismatch)
+}
+
+func TestLocalEd25519Signer(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	assert.NoError(t, err)
+	s, err := NewLocalEd25519Signer(privateKey)
+	assert.NoError(t, err)
+	assert.Equal(t, publicKey, s.PublicKey())
+
+	message := []byte("solana message")
+	signature, err := s.SignMessage(context.Background(), message)
+	assert.NoError(t, err)
+	assert.True(t, ed25519.Verify(publicKey, message, signature))
+
+	_, err = NewLocalEd25519Signer(privateKey.Seed())
+	assert.Error(t, err)
+}
@@ -0,0 +1,129 @@
+package slip10
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/15ho/wallet-utils-go/bip32"
+)
+
+// SLIP-0010: https://github.com/satoshilabs/slips/blob/master/slip-0010.md
+// Universal private key derivation from master private key.
+// Only the ed25519 curve is implemented here, use the bip32 package for secp256k1.
+// ed25519 does not support public parent key to public child key derivation, so every index must be hardened.
+
+const (
+	HardenedKeyStart = bip32.HardenedKeyStart
+
+	MinSeedLen = bip32.MinSeedLen
+	MaxSeedLen = bip32.MaxSeedLen
+)
+
+var ed25519Secret = []byte("ed25519 seed")
+
+var (
+	ErrInvalidSeedLen = fmt.Errorf("seed length must be between %d and %d bytes", MinSeedLen, MaxSeedLen)
+	ErrNotHardened    = errors.New("ed25519 only supports hardened derivation")
+	ErrMaxDepth       = bip32.ErrMaxDepth
+)
+
+// Key is a SLIP-0010 ed25519 private key with its chain code.
+type Key struct {
+	depth       uint8
+	childNumber uint32
+	chainCode   []byte // 32 bytes
+	key         []byte // 32 bytes, ed25519 private key seed
+}
+
+// NewMasterKey creates a master key from a seed, e.g. the output of bip39.CreateSeedFromMnemonic.
+func NewMasterKey(seed []byte) (*Key, error) {
+	if len(seed) < MinSeedLen || len(seed) > MaxSeedLen {
+		return nil, ErrInvalidSeedLen
+	}
+	key, chainCode := hmacSHA512(ed25519Secret, seed)
+	return &Key{
+		chainCode: chainCode,
+		key:       key,
+	}, nil
+}
+
+// Child derives the hardened child key at the given index.
+// The index must be >= HardenedKeyStart.
+func (k *Key) Child(index uint32) (*Key, error) {
+	if index < HardenedKeyStart {
+		return nil, ErrNotHardened
+	}
+	if k.depth == 255 {
+		return nil, ErrMaxDepth
+	}
+	// 0x00 || ser256(kpar) || ser32(i)
+	data := make([]byte, 0, 37)
+	data = append(data, 0x00)
+	data = append(data, k.key...)
+	data = binary.BigEndian.AppendUint32(data, index)
+	key, chainCode := hmacSHA512(k.chainCode, data)
+	return &Key{
+		depth:       k.depth + 1,
+		childNumber: index,
+		chainCode:   chainCode,
+		key:         key,
+	}, nil
+}
+
+// DerivePath derives the descendant key for a path such as "m/44'/501'/0'/0'".
+func (k *Key) DerivePath(path string) (*Key, error) {
+	indices, err := bip32.ParsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	key := k
+	for _, index := range indices {
+		key, err = key.Child(index)
+		if err != nil {
+			return nil, fmt.Errorf("derive %s: %w", path, err)
+		}
+	}
+	return key, nil
+}
+
+// Depth returns the number of derivations from the master key.
+func (k *Key) Depth() uint8 {
+	return k.depth
+}
+
+// ChildNumber returns the index this key was derived with.
+func (k *Key) ChildNumber() uint32 {
+	return k.childNumber
+}
+
+// ChainCode returns a copy of the 32-byte chain code.
+func (k *Key) ChainCode() []byte {
+	return bytes.Clone(k.chainCode)
+}
+
+// Seed returns a copy of the 32-byte private key, which is the ed25519 seed.
+func (k *Key) Seed() []byte {
+	return bytes.Clone(k.key)
+}
+
+// PrivateKey returns the 64-byte ed25519 private key (seed || public key).
+func (k *Key) PrivateKey() ed25519.PrivateKey {
+	return ed25519.NewKeyFromSeed(k.key)
+}
+
+// PublicKey returns the 32-byte ed25519 public key.
+func (k *Key) PublicKey() ed25519.PublicKey {
+	return k.PrivateKey().Public().(ed25519.PublicKey)
+}
+
+func hmacSHA512(secret, data []byte) (il, ir []byte) {
+	mac := hmac.New(sha512.New, secret)
+	_, _ = mac.Write(data)
+	sum := mac.Sum(nil)
+	return sum[:32], sum[32:]
+}
@@ -0,0 +1,61 @@
+package slip39
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// Passphrase encryption of the master secret, a 4 round Feistel network with PBKDF2 as the round function.
+// https://github.com/satoshilabs/slips/blob/master/slip-0039.md#encryption-of-the-master-secret
+
+const (
+	baseIterationCount = 10000
+	roundCount         = 4
+)
+
+var customizationString = []byte("shamir")
+
+func roundFunction(i byte, passphrase []byte, iterationExponent uint8, salt, r []byte) []byte {
+	password := append([]byte{i}, passphrase...)
+	iterations := (baseIterationCount << iterationExponent) / roundCount
+	return pbkdf2.Key(password, append(salt[:len(salt):len(salt)], r...), iterations, len(r), sha256.New)
+}
+
+func getSalt(identifier uint16, extendable bool) []byte {
+	if extendable {
+		return nil
+	}
+	return binary.BigEndian.AppendUint16(append([]byte{}, customizationString...), identifier)
+}
+
+func encrypt(masterSecret, passphrase []byte, iterationExponent uint8, identifier uint16, extendable bool) []byte {
+	half := len(masterSecret) / 2
+	l, r := masterSecret[:half], masterSecret[half:]
+	salt := getSalt(identifier, extendable)
+	for i := range byte(roundCount) {
+		f := roundFunction(i, passphrase, iterationExponent, salt, r)
+		l, r = r, xor(l, f)
+	}
+	return append(append([]byte{}, r...), l...)
+}
+
+func decrypt(encryptedSecret, passphrase []byte, iterationExponent uint8, identifier uint16, extendable bool) []byte {
+	half := len(encryptedSecret) / 2
+	l, r := encryptedSecret[:half], encryptedSecret[half:]
+	salt := getSalt(identifier, extendable)
+	for i := byte(roundCount); i > 0; i-- {
+		f := roundFunction(i-1, passphrase, iterationExponent, salt, r)
+		l, r = r, xor(l, f)
+	}
+	return append(append([]byte{}, r...), l...)
+}
+
+func xor(a, b []byte) []byte {
+	out := make([]byte, len(a))
+	for i := range a {
+		out[i] = a[i] ^ b[i]
+	}
+	return out
+}
@@ -0,0 +1,163 @@
+package slip39
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Shamir's secret sharing over GF(256) as specified by SLIP-0039.
+// https://github.com/satoshilabs/slips/blob/master/slip-0039.md#shamirs-secret-sharing
+
+const (
+	secretIndex       = 255
+	digestIndex       = 254
+	digestLengthBytes = 4
+)
+
+var errInvalidDigest = errors.New("invalid digest of the shared secret")
+
+// exp and log tables of GF(256) with the Rijndael polynomial x^8 + x^4 + x^3 + x + 1 and generator 3.
+var expTable, logTable = func() (exp [255]byte, log [256]byte) {
+	poly := 1
+	for i := range 255 {
+		exp[i] = byte(poly)
+		log[poly] = byte(i)
+		// multiply poly by the polynomial x + 1
+		poly = (poly << 1) ^ poly
+		if poly&0x100 != 0 {
+			poly ^= 0x11b
+		}
+	}
+	return
+}()
+
+type rawShare struct {
+	x     byte
+	value []byte
+}
+
+// interpolate returns f(x) of the polynomial going through the given points (Lagrange interpolation).
+func interpolate(shares []rawShare, x byte) ([]byte, error) {
+	if len(shares) == 0 {
+		return nil, errors.New("no shares to interpolate")
+	}
+	valueLen := len(shares[0].value)
+	seen := make(map[byte]bool, len(shares))
+	for _, share := range shares {
+		if seen[share.x] {
+			return nil, errors.New("share indices must be unique")
+		}
+		seen[share.x] = true
+		if len(share.value) != valueLen {
+			return nil, errors.New("all share values must have the same length")
+		}
+	}
+	for _, share := range shares {
+		if share.x == x {
+			return share.value, nil
+		}
+	}
+
+	// logProd = sum(log(x_i - x)), subtraction and addition are xor in GF(256)
+	logProd := 0
+	for _, share := range shares {
+		logProd += int(logTable[share.x^x])
+	}
+
+	result := make([]byte, valueLen)
+	for _, share := range shares {
+		// log of the Lagrange basis polynomial evaluated at x
+		logBasisEval := logProd - int(logTable[share.x^x])
+		for _, other := range shares {
+			logBasisEval -= int(logTable[share.x^other.x])
+		}
+		logBasisEval = ((logBasisEval % 255) + 255) % 255
+
+		for i, y := range share.value {
+			if y != 0 {
+				result[i] ^= expTable[(int(logTable[y])+logBasisEval)%255]
+			}
+		}
+	}
+	return result, nil
+}
+
+func createDigest(randomData, sharedSecret []byte) []byte {
+	mac := hmac.New(sha256.New, randomData)
+	_, _ = mac.Write(sharedSecret)
+	return mac.Sum(nil)[:digestLengthBytes]
+}
+
+func splitSecret(threshold, shareCount int, sharedSecret []byte, random io.Reader) ([]rawShare, error) {
+	if threshold < 1 {
+		return nil, errors.New("sharing threshold must be a positive integer")
+	}
+	if threshold > shareCount {
+		return nil, fmt.Errorf("sharing threshold %d must not exceed the number of shares %d", threshold, shareCount)
+	}
+	if shareCount > maxShareCount {
+		return nil, fmt.Errorf("number of shares %d must not exceed %d", shareCount, maxShareCount)
+	}
+
+	// if the threshold is 1, then the digest of the shared secret is not used
+	if threshold == 1 {
+		shares := make([]rawShare, 0, shareCount)
+		for i := range shareCount {
+			shares = append(shares, rawShare{x: byte(i), value: sharedSecret})
+		}
+		return shares, nil
+	}
+
+	randomShareCount := threshold - 2
+	shares := make([]rawShare, 0, shareCount)
+	for i := range randomShareCount {
+		value := make([]byte, len(sharedSecret))
+		if _, err := io.ReadFull(random, value); err != nil {
+			return nil, fmt.Errorf("read random share: %w", err)
+		}
+		shares = append(shares, rawShare{x: byte(i), value: value})
+	}
+
+	randomPart := make([]byte, len(sharedSecret)-digestLengthBytes)
+	if _, err := io.ReadFull(random, randomPart); err != nil {
+		return nil, fmt.Errorf("read random digest part: %w", err)
+	}
+	digest := createDigest(randomPart, sharedSecret)
+
+	baseShares := append(shares[:len(shares):len(shares)],
+		rawShare{x: digestIndex, value: append(digest, randomPart...)},
+		rawShare{x: secretIndex, value: sharedSecret},
+	)
+	for i := randomShareCount; i < shareCount; i++ {
+		value, err := interpolate(baseShares, byte(i))
+		if err != nil {
+			return nil, err
+		}
+		shares = append(shares, rawShare{x: byte(i), value: value})
+	}
+	return shares, nil
+}
+
+func recoverSecret(threshold int, shares []rawShare) ([]byte, error) {
+	// if the threshold is 1, then the digest of the shared secret is not used
+	if threshold == 1 {
+		return shares[0].value, nil
+	}
+
+	sharedSecret, err := interpolate(shares, secretIndex)
+	if err != nil {
+		return nil, err
+	}
+	digestShare, err := interpolate(shares, digestIndex)
+	if err != nil {
+		return nil, err
+	}
+	digest, randomPart := digestShare[:digestLengthBytes], digestShare[digestLengthBytes:]
+	if !hmac.Equal(digest, createDigest(randomPart, sharedSecret)) {
+		return nil, errInvalidDigest
+	}
+	return sharedSecret, nil
+}
@@ -0,0 +1,208 @@
+package slip39
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Share format:
+// https://github.com/satoshilabs/slips/blob/master/slip-0039.md#format-of-the-share-mnemonic
+//
+//	| id (15 bits) | ext (1 bit) | e (4 bits) | GI (4 bits) | Gt (4 bits) | g (4 bits) | I (4 bits) | t (4 bits) | ps (padded share value) | C (30 bits) |
+
+const (
+	radixBits               = 10 // each word encodes 10 bits
+	idLengthBits            = 15
+	extendableFlagBits      = 1
+	iterationExpLengthBits  = 4
+	idExpLengthWords        = 2 // (idLengthBits + extendableFlagBits + iterationExpLengthBits) / radixBits
+	checksumLengthWords     = 3
+	metadataLengthWords     = idExpLengthWords + 2 + checksumLengthWords
+	minStrengthBits         = 128
+	minMnemonicLengthWords  = metadataLengthWords + (minStrengthBits+radixBits-1)/radixBits
+	maxShareCount           = 16
+	maxIterationExponent    = 1<<iterationExpLengthBits - 1
+	customizationExtendable = "shamir_extendable"
+)
+
+// Share is a decoded SLIP-0039 share mnemonic.
+type Share struct {
+	Identifier        uint16
+	Extendable        bool
+	IterationExponent uint8
+	GroupIndex        uint8
+	GroupThreshold    uint8
+	GroupCount        uint8
+	MemberIndex       uint8
+	MemberThreshold   uint8
+	Value             []byte
+}
+
+// Mnemonic encodes the share as a mnemonic.
+func (s *Share) Mnemonic() string {
+	idExp := uint64(s.Identifier)<<(extendableFlagBits+iterationExpLengthBits) |
+		uint64(boolToUint(s.Extendable))<<iterationExpLengthBits |
+		uint64(s.IterationExponent)
+	groupParams := uint64(s.GroupIndex)<<16 |
+		uint64(s.GroupThreshold-1)<<12 |
+		uint64(s.GroupCount-1)<<8 |
+		uint64(s.MemberIndex)<<4 |
+		uint64(s.MemberThreshold-1)
+
+	valueWordCount := (len(s.Value)*8 + radixBits - 1) / radixBits
+
+	data := make([]int, 0, metadataLengthWords+valueWordCount)
+	data = append(data, intToIndices(new(big.Int).SetUint64(idExp), idExpLengthWords)...)
+	data = append(data, intToIndices(new(big.Int).SetUint64(groupParams), 2)...)
+	data = append(data, intToIndices(new(big.Int).SetBytes(s.Value), valueWordCount)...)
+	data = append(data, createChecksum(data, s.customization())...)
+
+	words := make([]string, 0, len(data))
+	for _, idx := range data {
+		words = append(words, wordList[idx])
+	}
+	return strings.Join(words, " ")
+}
+
+func (s *Share) customization() []byte {
+	if s.Extendable {
+		return []byte(customizationExtendable)
+	}
+	return customizationString
+}
+
+// commonParameters are the parameters that must be equal in all shares of one master secret.
+type commonParameters struct {
+	identifier        uint16
+	extendable        bool
+	iterationExponent uint8
+	groupThreshold    uint8
+	groupCount        uint8
+}
+
+func (s *Share) commonParameters() commonParameters {
+	return commonParameters{s.Identifier, s.Extendable, s.IterationExponent, s.GroupThreshold, s.GroupCount}
+}
+
+// ParseShare decodes a share mnemonic, verifying its checksum and padding.
+func ParseShare(mnemonic string) (*Share, error) {
+	words := strings.Fields(strings.ToLower(mnemonic))
+	if len(words) < minMnemonicLengthWords {
+		return nil, fmt.Errorf("%w: must be at least %d words, got %d", ErrInvalidMnemonicLength, minMnemonicLengthWords, len(words))
+	}
+	data := make([]int, 0, len(words))
+	for i, word := range words {
+		idx, ok := wordIndex[word]
+		if !ok {
+			return nil, fmt.Errorf("%w: %q at position %d", ErrUnknownWord, word, i+1)
+		}
+		data = append(data, idx)
+	}
+
+	paddingLen := (radixBits * (len(data) - metadataLengthWords)) % 16
+	if paddingLen > 8 {
+		return nil, fmt.Errorf("%w: %d words", ErrInvalidMnemonicLength, len(words))
+	}
+
+	idExp := indicesToInt(data[:idExpLengthWords]).Uint64()
+	s := &Share{
+		Identifier:        uint16(idExp >> (extendableFlagBits + iterationExpLengthBits)),
+		Extendable:        (idExp>>iterationExpLengthBits)&1 == 1,
+		IterationExponent: uint8(idExp & maxIterationExponent),
+	}
+	if !verifyChecksum(data, s.customization()) {
+		return nil, fmt.Errorf("%w: %s ...", ErrInvalidChecksum, strings.Join(words[:idExpLengthWords+2], " "))
+	}
+
+	groupParams := indicesToInt(data[idExpLengthWords : idExpLengthWords+2]).Uint64()
+	s.GroupIndex = uint8(groupParams >> 16 & 0xf)
+	s.GroupThreshold = uint8(groupParams>>12&0xf) + 1
+	s.GroupCount = uint8(groupParams>>8&0xf) + 1
+	s.MemberIndex = uint8(groupParams >> 4 & 0xf)
+	s.MemberThreshold = uint8(groupParams&0xf) + 1
+	if s.GroupCount < s.GroupThreshold {
+		return nil, fmt.Errorf("%w: group threshold %d exceeds the group count %d", ErrInvalidShare, s.GroupThreshold, s.GroupCount)
+	}
+
+	valueData := data[idExpLengthWords+2 : len(data)-checksumLengthWords]
+	valueByteCount := (radixBits*len(valueData) - paddingLen) / 8
+	valueInt := indicesToInt(valueData)
+	if valueInt.BitLen() > valueByteCount*8 {
+		return nil, fmt.Errorf("%w: invalid padding", ErrInvalidShare)
+	}
+	s.Value = valueInt.FillBytes(make([]byte, valueByteCount))
+	return s, nil
+}
+
+func intToIndices(value *big.Int, length int) []int {
+	indices := make([]int, length)
+	mask := big.NewInt(1<<radixBits - 1)
+	v := new(big.Int).Set(value)
+	for i := length - 1; i >= 0; i-- {
+		indices[i] = int(new(big.Int).And(v, mask).Int64())
+		v.Rsh(v, radixBits)
+	}
+	return indices
+}
+
+func indicesToInt(indices []int) *big.Int {
+	value := new(big.Int)
+	for _, idx := range indices {
+		value.Lsh(value, radixBits)
+		value.Or(value, big.NewInt(int64(idx)))
+	}
+	return value
+}
+
+func boolToUint(b bool) uint {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// RS1024 checksum, a Reed-Solomon code over GF(1024).
+// https://github.com/satoshilabs/slips/blob/master/slip-0039.md#checksum
+
+var rs1024Gen = [10]uint32{
+	0xe0e040, 0x1c1c080, 0x3838100, 0x7070200, 0xe0e0009,
+	0x1c0c2412, 0x38086c24, 0x3090fc48, 0x21b1f890, 0x3f3f120,
+}
+
+func rs1024Polymod(values []int) uint32 {
+	chk := uint32(1)
+	for _, v := range values {
+		b := chk >> 20
+		chk = (chk&0xfffff)<<10 ^ uint32(v)
+		for i := range 10 {
+			if (b>>i)&1 == 1 {
+				chk ^= rs1024Gen[i]
+			}
+		}
+	}
+	return chk
+}
+
+func customizationValues(customization []byte) []int {
+	values := make([]int, 0, len(customization))
+	for _, c := range customization {
+		values = append(values, int(c))
+	}
+	return values
+}
+
+func createChecksum(data []int, customization []byte) []int {
+	values := append(customizationValues(customization), data...)
+	values = append(values, make([]int, checksumLengthWords)...)
+	polymod := rs1024Polymod(values) ^ 1
+	checksum := make([]int, checksumLengthWords)
+	for i := range checksumLengthWords {
+		checksum[i] = int(polymod>>(10*(checksumLengthWords-1-i))) & 1023
+	}
+	return checksum
+}
+
+func verifyChecksum(data []int, customization []byte) bool {
+	return rs1024Polymod(append(customizationValues(customization), data...)) == 1
+}
@@ -0,0 +1,233 @@
+package slip39
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/15ho/wallet-utils-go/slip39/wordlist"
+)
+
+// SLIP-0039: https://github.com/satoshilabs/slips/blob/master/slip-0039.md
+// This SLIP describes a standard and interoperable implementation of Shamir's secret sharing (SSS).
+// SSS splits a secret into unique parts which can be distributed among participants, and requires
+// a specified minimum number of parts to be supplied in order to reconstruct the original secret.
+// Shares are organized in groups: GroupThreshold groups are needed, and each group needs MemberThreshold of its shares.
+
+// python implementation: https://github.com/trezor/python-shamir-mnemonic
+
+// randReader is the entropy source of GenerateMnemonics.
+var randReader io.Reader = rand.Reader
+
+var (
+	ErrInvalidMnemonicLength = errors.New("invalid mnemonic length")
+	ErrUnknownWord           = errors.New("unknown mnemonic word")
+	ErrInvalidChecksum       = errors.New("invalid mnemonic checksum")
+	ErrInvalidShare          = errors.New("invalid share")
+	ErrInvalidShareSet       = errors.New("invalid set of shares")
+	ErrInsufficientShares    = errors.New("insufficient number of shares")
+	ErrInvalidDigest         = errors.New("invalid shares: digest mismatch, the shares belong to different secrets or are corrupted")
+	ErrInvalidPassphrase     = errors.New("passphrase must contain only printable ASCII characters")
+	ErrInvalidMasterSecret   = fmt.Errorf("master secret must be at least %d bits and its length must be a multiple of 16 bits", minStrengthBits)
+)
+
+var (
+	wordList  []string
+	wordIndex map[string]int
+)
+
+func init() {
+	wordList = strings.Split(strings.TrimSpace(wordlist.SLIP39), "\n")
+	if len(wordList) != 1<<radixBits {
+		panic(fmt.Sprintf("invalid slip39 word list length: %d", len(wordList)))
+	}
+	wordIndex = make(map[string]int, len(wordList))
+	for i, word := range wordList {
+		wordIndex[word] = i
+	}
+}
+
+// GroupParameters configures one group: MemberThreshold of MemberCount member shares recover the group.
+type GroupParameters struct {
+	MemberThreshold int
+	MemberCount     int
+}
+
+// GenerateOptions are the optional parameters of GenerateMnemonics.
+type GenerateOptions struct {
+	// IterationExponent sets the PBKDF2 iterations to 10000 * 2^IterationExponent, from 0 to 15.
+	IterationExponent uint8
+	// Extendable allows creating more shares of the same secret later, with the same identifier.
+	Extendable bool
+}
+
+// DefaultGenerateOptions are the options used when GenerateMnemonics is called without options.
+var DefaultGenerateOptions = GenerateOptions{
+	IterationExponent: 1,
+	Extendable:        true,
+}
+
+// GenerateMnemonics splits a master secret into mnemonic shares.
+// groupThreshold groups out of len(groups) are required to recover the secret.
+// The master secret is encrypted with the passphrase, pass "" if none.
+// The result contains the mnemonics of each group, in the order of groups.
+//
+// A seed created by bip39.CreateSeedFromMnemonic (64 bytes) can be used as the master secret.
+func GenerateMnemonics(groupThreshold int, groups []GroupParameters, masterSecret []byte, passphrase string, optsOption ...GenerateOptions) ([][]string, error) {
+	opts := DefaultGenerateOptions
+	if len(optsOption) > 0 {
+		opts = optsOption[0]
+	}
+	if len(masterSecret)*8 < minStrengthBits || len(masterSecret)%2 != 0 {
+		return nil, ErrInvalidMasterSecret
+	}
+	if opts.IterationExponent > maxIterationExponent {
+		return nil, fmt.Errorf("iteration exponent must be between 0 and %d", maxIterationExponent)
+	}
+	if groupThreshold < 1 || groupThreshold > len(groups) {
+		return nil, fmt.Errorf("group threshold %d must be between 1 and the number of groups %d", groupThreshold, len(groups))
+	}
+	if len(groups) > maxShareCount {
+		return nil, fmt.Errorf("number of groups %d must not exceed %d", len(groups), maxShareCount)
+	}
+	for _, group := range groups {
+		if group.MemberThreshold == 1 && group.MemberCount > 1 {
+			return nil, errors.New("creating multiple member shares with member threshold 1 is not allowed, use 1-of-1 member sharing instead")
+		}
+	}
+	pass, err := checkPassphrase(passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	var idBytes [2]byte
+	if _, err := io.ReadFull(randReader, idBytes[:]); err != nil {
+		return nil, fmt.Errorf("read random identifier: %w", err)
+	}
+	identifier := binary.BigEndian.Uint16(idBytes[:]) & (1<<idLengthBits - 1)
+
+	encryptedSecret := encrypt(masterSecret, pass, opts.IterationExponent, identifier, opts.Extendable)
+
+	groupShares, err := splitSecret(groupThreshold, len(groups), encryptedSecret, randReader)
+	if err != nil {
+		return nil, fmt.Errorf("split master secret: %w", err)
+	}
+
+	mnemonics := make([][]string, 0, len(groups))
+	for i, group := range groups {
+		memberShares, err := splitSecret(group.MemberThreshold, group.MemberCount, groupShares[i].value, randReader)
+		if err != nil {
+			return nil, fmt.Errorf("split group %d: %w", i, err)
+		}
+		groupMnemonics := make([]string, 0, len(memberShares))
+		for _, memberShare := range memberShares {
+			share := Share{
+				Identifier:        identifier,
+				Extendable:        opts.Extendable,
+				IterationExponent: opts.IterationExponent,
+				GroupIndex:        groupShares[i].x,
+				GroupThreshold:    uint8(groupThreshold),
+				GroupCount:        uint8(len(groups)),
+				MemberIndex:       memberShare.x,
+				MemberThreshold:   uint8(group.MemberThreshold),
+				Value:             memberShare.value,
+			}
+			groupMnemonics = append(groupMnemonics, share.Mnemonic())
+		}
+		mnemonics = append(mnemonics, groupMnemonics)
+	}
+	return mnemonics, nil
+}
+
+// CombineMnemonics recovers the master secret from mnemonic shares.
+// Exactly the threshold number of groups, each with exactly its member threshold of shares, must be provided.
+func CombineMnemonics(mnemonics []string, passphrase string) ([]byte, error) {
+	if len(mnemonics) == 0 {
+		return nil, fmt.Errorf("%w: no mnemonics provided", ErrInsufficientShares)
+	}
+	pass, err := checkPassphrase(passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	shares := make([]*Share, 0, len(mnemonics))
+	for i, mnemonic := range mnemonics {
+		share, err := ParseShare(mnemonic)
+		if err != nil {
+			return nil, fmt.Errorf("mnemonic %d: %w", i+1, err)
+		}
+		shares = append(shares, share)
+	}
+
+	params := shares[0].commonParameters()
+	groups := make(map[uint8][]*Share)
+	for _, share := range shares {
+		if share.commonParameters() != params {
+			return nil, fmt.Errorf("%w: all mnemonics must begin with the same %d words and have the same group threshold and count", ErrInvalidShareSet, idExpLengthWords)
+		}
+		group := groups[share.GroupIndex]
+		if len(group) > 0 && group[0].MemberThreshold != share.MemberThreshold {
+			return nil, fmt.Errorf("%w: member thresholds of group %d differ", ErrInvalidShareSet, share.GroupIndex)
+		}
+		if slices.ContainsFunc(group, func(s *Share) bool {
+			return s.MemberIndex == share.MemberIndex && bytes.Equal(s.Value, share.Value)
+		}) {
+			continue // the same mnemonic was given twice
+		}
+		groups[share.GroupIndex] = append(group, share)
+	}
+
+	if len(groups) < int(params.groupThreshold) {
+		return nil, fmt.Errorf("%w: the required number of groups is %d, got %d", ErrInsufficientShares, params.groupThreshold, len(groups))
+	}
+	if len(groups) != int(params.groupThreshold) {
+		return nil, fmt.Errorf("%w: expected %d groups, got %d", ErrInvalidShareSet, params.groupThreshold, len(groups))
+	}
+
+	groupShares := make([]rawShare, 0, len(groups))
+	for groupIndex, group := range groups {
+		memberThreshold := int(group[0].MemberThreshold)
+		if len(group) != memberThreshold {
+			return nil, fmt.Errorf("%w: group %d requires %d mnemonics, got %d", ErrInsufficientShares, groupIndex, memberThreshold, len(group))
+		}
+		memberShares := make([]rawShare, 0, len(group))
+		for _, share := range group {
+			memberShares = append(memberShares, rawShare{x: share.MemberIndex, value: share.Value})
+		}
+		groupSecret, err := recoverSecret(memberThreshold, memberShares)
+		if err != nil {
+			return nil, fmt.Errorf("recover group %d: %w", groupIndex, wrapShareError(err))
+		}
+		groupShares = append(groupShares, rawShare{x: groupIndex, value: groupSecret})
+	}
+
+	encryptedSecret, err := recoverSecret(int(params.groupThreshold), groupShares)
+	if err != nil {
+		return nil, wrapShareError(err)
+	}
+	if len(encryptedSecret)*8 < minStrengthBits || len(encryptedSecret)%2 != 0 {
+		return nil, ErrInvalidMasterSecret
+	}
+	return decrypt(encryptedSecret, pass, params.iterationExponent, params.identifier, params.extendable), nil
+}
+
+func wrapShareError(err error) error {
+	if errors.Is(err, errInvalidDigest) {
+		return ErrInvalidDigest
+	}
+	return fmt.Errorf("%w: %w", ErrInvalidShareSet, err)
+}
+
+func checkPassphrase(passphrase string) ([]byte, error) {
+	for _, c := range []byte(passphrase) {
+		if c < 32 || c > 126 {
+			return nil, ErrInvalidPassphrase
+		}
+	}
+	return []byte(passphrase), nil
+}
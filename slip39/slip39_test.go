@@ -0,0 +1,186 @@
+package slip39
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/15ho/wallet-utils-go/bip32"
+	"github.com/15ho/wallet-utils-go/bip39"
+	"github.com/stretchr/testify/assert"
+)
+
+// testdata/vectors.json is https://github.com/trezor/python-shamir-mnemonic/blob/v0.3.0/vectors.json unchanged:
+// [description, mnemonics, master secret hex, BIP-32 master xprv], the secret and xprv are empty if the mnemonics are invalid.
+func TestVectors(t *testing.T) {
+	data, err := os.ReadFile("testdata/vectors.json")
+	assert.NoError(t, err)
+	var vectors [][]json.RawMessage
+	assert.NoError(t, json.Unmarshal(data, &vectors))
+	assert.NotEmpty(t, vectors)
+
+	for _, v := range vectors {
+		testVector(t, v)
+	}
+}
+
+func testVector(t *testing.T, v []json.RawMessage) {
+	var (
+		description, masterSecret, xprv string
+		mnemonics                       []string
+	)
+	assert.Len(t, v, 4)
+	assert.NoError(t, json.Unmarshal(v[0], &description))
+	assert.NoError(t, json.Unmarshal(v[1], &mnemonics))
+	assert.NoError(t, json.Unmarshal(v[2], &masterSecret))
+	assert.NoError(t, json.Unmarshal(v[3], &xprv))
+
+	t.Run(description, func(t *testing.T) {
+		secret, err := CombineMnemonics(mnemonics, "TREZOR")
+		if masterSecret == "" {
+			assert.Error(t, err)
+			return
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, masterSecret, hex.EncodeToString(secret))
+
+		masterKey, err := bip32.NewMasterKey(secret)
+		assert.NoError(t, err)
+		assert.Equal(t, xprv, masterKey.String())
+	})
+}
+
+func TestGenerateAndCombine(t *testing.T) {
+	masterSecret, _ := hex.DecodeString("0c94b1a3e4b0e5a5e5f1e7f7c2b6d8a9")
+	passphrase := "TREZOR"
+
+	t.Run("single group", func(t *testing.T) {
+		mnemonics, err := GenerateMnemonics(1, []GroupParameters{{3, 5}}, masterSecret, passphrase)
+		assert.NoError(t, err)
+		assert.Len(t, mnemonics, 1)
+		assert.Len(t, mnemonics[0], 5)
+
+		for _, subset := range [][]string{
+			mnemonics[0][:3],
+			mnemonics[0][2:],
+			{mnemonics[0][4], mnemonics[0][0], mnemonics[0][2]},
+		} {
+			secret, err := CombineMnemonics(subset, passphrase)
+			assert.NoError(t, err)
+			assert.Equal(t, masterSecret, secret)
+		}
+
+		_, err = CombineMnemonics(mnemonics[0][:2], passphrase)
+		assert.ErrorIs(t, err, ErrInsufficientShares)
+
+		// the same share twice does not count as two shares
+		_, err = CombineMnemonics([]string{mnemonics[0][0], mnemonics[0][0], mnemonics[0][1]}, passphrase)
+		assert.ErrorIs(t, err, ErrInsufficientShares)
+
+		// a wrong passphrase silently yields a different secret
+		secret, err := CombineMnemonics(mnemonics[0][:3], "")
+		assert.NoError(t, err)
+		assert.NotEqual(t, masterSecret, secret)
+	})
+
+	t.Run("multiple groups", func(t *testing.T) {
+		mnemonics, err := GenerateMnemonics(2, []GroupParameters{{1, 1}, {2, 3}, {3, 5}}, masterSecret, passphrase)
+		assert.NoError(t, err)
+		assert.Len(t, mnemonics, 3)
+
+		secret, err := CombineMnemonics(append([]string{mnemonics[0][0]}, mnemonics[2][1:4]...), passphrase)
+		assert.NoError(t, err)
+		assert.Equal(t, masterSecret, secret)
+
+		secret, err = CombineMnemonics(append([]string{mnemonics[1][2], mnemonics[1][0]}, mnemonics[2][:3]...), passphrase)
+		assert.NoError(t, err)
+		assert.Equal(t, masterSecret, secret)
+
+		_, err = CombineMnemonics(mnemonics[2][:3], passphrase)
+		assert.ErrorIs(t, err, ErrInsufficientShares)
+
+		_, err = CombineMnemonics(append([]string{mnemonics[0][0]}, mnemonics[1][:1]...), passphrase)
+		assert.ErrorIs(t, err, ErrInsufficientShares)
+	})
+
+	t.Run("non-extendable", func(t *testing.T) {
+		mnemonics, err := GenerateMnemonics(1, []GroupParameters{{2, 3}}, masterSecret, passphrase, GenerateOptions{IterationExponent: 0})
+		assert.NoError(t, err)
+
+		share, err := ParseShare(mnemonics[0][0])
+		assert.NoError(t, err)
+		assert.False(t, share.Extendable)
+		assert.EqualValues(t, 0, share.IterationExponent)
+		assert.Equal(t, mnemonics[0][0], share.Mnemonic())
+
+		secret, err := CombineMnemonics(mnemonics[0][1:], passphrase)
+		assert.NoError(t, err)
+		assert.Equal(t, masterSecret, secret)
+	})
+
+	t.Run("bip39 seed", func(t *testing.T) {
+		seed := bip39.CreateSeedFromMnemonic("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about")
+		mnemonics, err := GenerateMnemonics(2, []GroupParameters{{2, 3}, {2, 3}}, seed, "")
+		assert.NoError(t, err)
+		assert.Len(t, strings.Fields(mnemonics[0][0]), 59) // 7 metadata words + 52 words for 512 bits
+
+		secret, err := CombineMnemonics(append(mnemonics[0][:2], mnemonics[1][1:]...), "")
+		assert.NoError(t, err)
+		assert.Equal(t, seed, secret)
+	})
+
+	t.Run("mixed secrets", func(t *testing.T) {
+		mnemonics1, err := GenerateMnemonics(1, []GroupParameters{{2, 3}}, masterSecret, passphrase)
+		assert.NoError(t, err)
+		mnemonics2, err := GenerateMnemonics(1, []GroupParameters{{2, 3}}, masterSecret, passphrase)
+		assert.NoError(t, err)
+		_, err = CombineMnemonics([]string{mnemonics1[0][0], mnemonics2[0][1]}, passphrase)
+		assert.ErrorIs(t, err, ErrInvalidShareSet)
+	})
+}
+
+func TestGenerateMnemonicsInvalid(t *testing.T) {
+	masterSecret := make([]byte, 16)
+
+	_, err := GenerateMnemonics(1, []GroupParameters{{1, 1}}, make([]byte, 15), "")
+	assert.ErrorIs(t, err, ErrInvalidMasterSecret)
+
+	_, err = GenerateMnemonics(1, []GroupParameters{{1, 1}}, make([]byte, 17), "")
+	assert.ErrorIs(t, err, ErrInvalidMasterSecret)
+
+	_, err = GenerateMnemonics(2, []GroupParameters{{1, 1}}, masterSecret, "")
+	assert.Error(t, err)
+
+	_, err = GenerateMnemonics(1, []GroupParameters{{1, 3}}, masterSecret, "")
+	assert.Error(t, err)
+
+	_, err = GenerateMnemonics(1, []GroupParameters{{4, 3}}, masterSecret, "")
+	assert.Error(t, err)
+
+	_, err = GenerateMnemonics(1, []GroupParameters{{2, 17}}, masterSecret, "")
+	assert.Error(t, err)
+
+	_, err = GenerateMnemonics(1, []GroupParameters{{1, 1}}, masterSecret, "pässphrase")
+	assert.ErrorIs(t, err, ErrInvalidPassphrase)
+}
+
+func TestParseShare(t *testing.T) {
+	_, err := ParseShare("duckling enlarge academic academic agency result length solution fridge kidney coal piece deal husband erode duke ajar critical decision")
+	assert.ErrorIs(t, err, ErrInvalidMnemonicLength)
+
+	_, err = ParseShare("duckling enlarge academic academic agency result length solution fridge kidney coal piece deal husband erode duke ajar critical decision keybord")
+	assert.ErrorIs(t, err, ErrUnknownWord)
+	assert.ErrorContains(t, err, `"keybord" at position 20`)
+
+	_, err = ParseShare("duckling enlarge academic academic agency result length solution fridge kidney coal piece deal husband erode duke ajar critical decision kidney")
+	assert.ErrorIs(t, err, ErrInvalidChecksum)
+
+	share, err := ParseShare("Duckling Enlarge academic academic agency result length solution fridge kidney coal piece deal husband erode duke ajar critical decision keyboard")
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, share.GroupThreshold)
+	assert.EqualValues(t, 1, share.GroupCount)
+	assert.EqualValues(t, 1, share.MemberThreshold)
+	assert.Len(t, share.Value, 16)
+}
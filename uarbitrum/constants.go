@@ -2,6 +2,7 @@ package uarbitrum
 
 import (
 	"strings"
+	"time"
 
 	"github.com/15ho/wallet-utils-go/uethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
@@ -19,6 +20,16 @@ func init() {
 
 var erc20ABI = uethereum.GetERC20ABI()
 
+// L2GasOracleOptions are the gas oracle options of Arbitrum One, its blocks are produced about every 250ms.
+// The L2 priority fee is ignored by the sequencer, the transactions are ordered first come, first served.
+// https://docs.arbitrum.io/how-arbitrum-works/gas-fees
+var L2GasOracleOptions = uethereum.GasOracleOptions{
+	Blocks:            uethereum.FeeHistoryBlocks,
+	RewardPercentiles: uethereum.DefaultGasOracleOptions.RewardPercentiles,
+	BaseFeeMultiplier: uethereum.BaseFeeMultiplier,
+	BlockTime:         250 * time.Millisecond,
+}
+
 var outboundTransferDataArgs abi.Arguments
 
 func init() {
@@ -2,10 +2,12 @@ package uarbitrum
 
 import (
 	"context"
-	"crypto/ecdsa"
 	"fmt"
 	"math/big"
 
+	"github.com/15ho/wallet-utils-go/keystore"
+	"github.com/15ho/wallet-utils-go/signer"
+	"github.com/15ho/wallet-utils-go/uethereum"
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -17,16 +19,22 @@ type WalletClient struct {
 	l1cli *ethclient.Client
 	l2cli *ethclient.Client
 
-	privateKey *ecdsa.PrivateKey
-	account    common.Address
+	signer  signer.Secp256k1Signer
+	account common.Address
+	nonces  *uethereum.NonceManager // optional
 }
 
 func NewWalletClient(ethEndpoint, arbEndpoint, privateKeyHex string) (*WalletClient, error) {
-	privateKey, err := crypto.ToECDSA(common.FromHex(privateKeyHex))
+	s, err := signer.NewLocalSecp256k1SignerFromHex(privateKeyHex)
 	if err != nil {
-		return nil, fmt.Errorf("invalid private key: %v", err)
+		return nil, err
 	}
+	return NewWalletClientFromSigner(ethEndpoint, arbEndpoint, s)
+}
 
+// NewWalletClientFromSigner creates a WalletClient whose L1 transactions are signed by s,
+// the private key is never touched by the WalletClient.
+func NewWalletClientFromSigner(ethEndpoint, arbEndpoint string, s signer.Secp256k1Signer) (*WalletClient, error) {
 	l1cli, err := ethclient.Dial(ethEndpoint)
 	if err != nil {
 		return nil, fmt.Errorf("L1 dial: %v", err)
@@ -38,13 +46,70 @@ func NewWalletClient(ethEndpoint, arbEndpoint, privateKeyHex string) (*WalletCli
 	}
 
 	return &WalletClient{
-		l1cli:      l1cli,
-		l2cli:      l2cli,
-		privateKey: privateKey,
-		account:    crypto.PubkeyToAddress(privateKey.PublicKey),
+		l1cli:   l1cli,
+		l2cli:   l2cli,
+		signer:  s,
+		account: crypto.PubkeyToAddress(*s.PublicKey()),
 	}, nil
 }
 
+// L1GasOracle returns a gas oracle of the parent chain (Ethereum), uethereum.DefaultGasOracleOptions is used without options.
+func (wc *WalletClient) L1GasOracle(optsOption ...uethereum.GasOracleOptions) *uethereum.GasOracle {
+	return uethereum.NewGasOracle(wc.l1cli, optsOption...)
+}
+
+// L2GasOracle returns a gas oracle of Arbitrum, L2GasOracleOptions is used without options
+// and its block time when the options have none.
+func (wc *WalletClient) L2GasOracle(optsOption ...uethereum.GasOracleOptions) *uethereum.GasOracle {
+	opts := L2GasOracleOptions
+	if len(optsOption) > 0 {
+		opts = optsOption[0]
+	}
+	if opts.BlockTime == 0 {
+		opts.BlockTime = L2GasOracleOptions.BlockTime
+	}
+	return uethereum.NewGasOracle(wc.l2cli, opts)
+}
+
+// L1ConfirmationTracker returns a confirmation tracker of the L1 transactions sent by the WalletClient (approvals and deposits),
+// uethereum.DefaultConfirmationOptions is used without options.
+func (wc *WalletClient) L1ConfirmationTracker(optsOption ...uethereum.ConfirmationOptions) *uethereum.ConfirmationTracker {
+	return uethereum.NewConfirmationTracker(wc.l1cli, optsOption...)
+}
+
+// SetNonceManager makes the WalletClient take the nonces of its L1 transactions from nm instead of the pending nonce of the chain,
+// so concurrent sends from the account get different nonces. nm must belong to the account of the WalletClient.
+func (wc *WalletClient) SetNonceManager(nm *uethereum.NonceManager) error {
+	if nm != nil && nm.Account() != wc.account {
+		return fmt.Errorf("nonce manager of %s, not %s", nm.Account(), wc.account)
+	}
+	wc.nonces = nm
+	return nil
+}
+
+// nextNonce returns the nonce of a new transaction, done must be called with the error of sending it.
+func (wc *WalletClient) nextNonce(ctx context.Context) (nonce uint64, done func(sendErr error), err error) {
+	if wc.nonces != nil {
+		return wc.nonces.Acquire(ctx)
+	}
+	nonce, err = wc.l1cli.PendingNonceAt(ctx, wc.account)
+	done = func(error) {}
+	return
+}
+
+// NewWalletClientFromKeystore opens a keystore v3 JSON file with the passphrase, see keystore.WriteKeyFile.
+func NewWalletClientFromKeystore(ethEndpoint, arbEndpoint, keystorePath, passphrase string) (*WalletClient, error) {
+	privateKey, err := keystore.ReadKeyFile(keystorePath, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("open keystore: %w", err)
+	}
+	s, err := signer.NewLocalSecp256k1SignerFromBytes(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	return NewWalletClientFromSigner(ethEndpoint, arbEndpoint, s)
+}
+
 func (wc *WalletClient) ApproveL1Token(ctx context.Context,
 	tokenAddress, l1GatewayRouterAddress string,
 	amount *big.Int,
@@ -70,11 +135,12 @@ func (wc *WalletClient) ApproveL1Token(ctx context.Context,
 	l1GatewayAddr := common.BytesToAddress(l1GatewayAddrBytes)
 	l1GatewayAddress = l1GatewayAddr.String()
 
-	nonce, err := wc.l1cli.PendingNonceAt(ctx, wc.account)
+	nonce, done, err := wc.nextNonce(ctx)
 	if err != nil {
 		err = fmt.Errorf("get nonce: %w", err)
 		return
 	}
+	defer func() { done(err) }()
 	chainID, err := wc.l1cli.ChainID(ctx)
 	if err != nil {
 		err = fmt.Errorf("get chain id: %w", err)
@@ -86,7 +152,7 @@ func (wc *WalletClient) ApproveL1Token(ctx context.Context,
 		return
 	}
 	tx := types.NewTransaction(nonce, tokenAddr, nil, gasLimit, gasPrice, approveData)
-	tx, err = types.SignTx(tx, types.NewEIP155Signer(chainID), wc.privateKey)
+	tx, err = signer.SignEVMTx(ctx, wc.signer, tx, types.NewEIP155Signer(chainID))
 	if err != nil {
 		err = fmt.Errorf("sign tx: %w", err)
 		return
@@ -112,11 +178,12 @@ func (wc *WalletClient) DepositL1Token(ctx context.Context,
 	amount, l1tol2Fee *big.Int,
 	gasLimit uint64, maxFeePerGas, maxPriorityFeePerGas *big.Int,
 	maxGas, gasPriceBid, maxSubmissionCost *big.Int) (txHash string, err error) {
-	nonce, err := wc.l1cli.PendingNonceAt(ctx, wc.account)
+	nonce, done, err := wc.nextNonce(ctx)
 	if err != nil {
 		err = fmt.Errorf("get nonce: %w", err)
 		return
 	}
+	defer func() { done(err) }()
 	chainID, err := wc.l1cli.ChainID(ctx)
 	if err != nil {
 		err = fmt.Errorf("get chain id: %w", err)
@@ -143,7 +210,7 @@ func (wc *WalletClient) DepositL1Token(ctx context.Context,
 		Value:     l1tol2Fee,
 		Data:      data,
 	})
-	tx, err = types.SignTx(tx, types.NewLondonSigner(chainID), wc.privateKey)
+	tx, err = signer.SignEVMTx(ctx, wc.signer, tx, types.NewLondonSigner(chainID))
 	if err != nil {
 		err = fmt.Errorf("sign tx: %w", err)
 		return
@@ -0,0 +1,185 @@
+package uethereum
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/15ho/wallet-utils-go/internal/zlog"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"go.uber.org/zap"
+)
+
+// ErrTxDropped is returned when a transaction will not be included: the node forgot it, or its nonce was used by another transaction.
+var ErrTxDropped = errors.New("transaction dropped")
+
+// ConfirmationOptions configures a ConfirmationTracker.
+type ConfirmationOptions struct {
+	// Confirmations is the number of blocks including and on top of the block of the transaction, 1 returns once it is included.
+	Confirmations uint64
+	// PollInterval is the time between two polls of the node.
+	PollInterval time.Duration
+	// DroppedTimeout is how long the node may not know the transaction before it is considered dropped.
+	DroppedTimeout time.Duration
+}
+
+// DefaultConfirmationOptions are the options for Ethereum mainnet, used when a ConfirmationTracker is created without options.
+var DefaultConfirmationOptions = ConfirmationOptions{
+	Confirmations:  12,
+	PollInterval:   4 * time.Second,
+	DroppedTimeout: 5 * time.Minute,
+}
+
+// ConfirmationTracker waits for sent transactions to be confirmed.
+type ConfirmationTracker struct {
+	tp   *TxParser
+	opts ConfirmationOptions
+}
+
+// NewConfirmationTracker creates a confirmation tracker, the zero PollInterval and DroppedTimeout are set from
+// DefaultConfirmationOptions and zero Confirmations is 1.
+func NewConfirmationTracker(cli *ethclient.Client, optsOption ...ConfirmationOptions) *ConfirmationTracker {
+	opts := DefaultConfirmationOptions
+	if len(optsOption) > 0 {
+		opts = optsOption[0]
+	}
+	if opts.Confirmations == 0 {
+		opts.Confirmations = 1
+	}
+	if opts.PollInterval == 0 {
+		opts.PollInterval = DefaultConfirmationOptions.PollInterval
+	}
+	if opts.DroppedTimeout == 0 {
+		opts.DroppedTimeout = DefaultConfirmationOptions.DroppedTimeout
+	}
+	return &ConfirmationTracker{tp: &TxParser{cli: cli}, opts: opts}
+}
+
+// ConfirmationTracker returns a confirmation tracker of the transactions sent by the WalletClient.
+func (wc *WalletClient) ConfirmationTracker(optsOption ...ConfirmationOptions) *ConfirmationTracker {
+	return NewConfirmationTracker(wc.cli, optsOption...)
+}
+
+// ConfirmationTracker returns a confirmation tracker using the node of the TxParser.
+func (tp *TxParser) ConfirmationTracker(optsOption ...ConfirmationOptions) *ConfirmationTracker {
+	return NewConfirmationTracker(tp.cli, optsOption...)
+}
+
+// trackedTx is the state of a transaction between two polls.
+type trackedTx struct {
+	hash          common.Hash
+	tx            *types.Transaction // last seen by the node
+	from          common.Address
+	notFoundSince time.Time
+}
+
+// Wait polls the node until the transaction has Confirmations confirmations and returns it parsed like TxParser.ParseBlock,
+// a failed transaction is returned with the status "fail".
+// ErrTxDropped is returned when the transaction is unknown to the node for DroppedTimeout,
+// or when another transaction with its nonce was included, e.g. after ReplaceTransaction or CancelTransaction.
+// A reorg of the block of the transaction restarts the count.
+func (ct *ConfirmationTracker) Wait(ctx context.Context, txHash string) (ptx *ParsedTx, err error) {
+	tt := &trackedTx{hash: common.HexToHash(txHash)}
+	ticker := time.NewTicker(ct.opts.PollInterval)
+	defer ticker.Stop()
+	for {
+		var confirmed bool
+		if ptx, confirmed, err = ct.poll(ctx, tt); err != nil || confirmed {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			err = ctx.Err()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (ct *ConfirmationTracker) poll(ctx context.Context, tt *trackedTx) (ptx *ParsedTx, confirmed bool, err error) {
+	cli := ct.tp.cli
+	receipt, err := cli.TransactionReceipt(ctx, tt.hash)
+	if err == nil {
+		return ct.parseIfConfirmed(ctx, tt, receipt)
+	}
+	if !errors.Is(err, ethereum.NotFound) {
+		err = fmt.Errorf("transaction receipt: %w", err)
+		return
+	}
+
+	// not included yet
+	tx, _, err := cli.TransactionByHash(ctx, tt.hash)
+	switch {
+	case err == nil:
+		if tt.tx == nil {
+			if tt.from, err = getTxFrom(tx); err != nil {
+				err = fmt.Errorf("get tx sender: %w", err)
+				return
+			}
+		}
+		tt.tx = tx
+		tt.notFoundSince = time.Time{}
+	case errors.Is(err, ethereum.NotFound):
+		if tt.notFoundSince.IsZero() {
+			tt.notFoundSince = time.Now()
+		} else if time.Since(tt.notFoundSince) > ct.opts.DroppedTimeout {
+			err = fmt.Errorf("%w: %s is unknown to the node for %s", ErrTxDropped, tt.hash.Hex(), ct.opts.DroppedTimeout)
+			return
+		}
+	default:
+		err = fmt.Errorf("transaction by hash: %w", err)
+		return
+	}
+	if tt.tx == nil {
+		err = nil
+		return
+	}
+
+	// the nonce of the account is above the nonce of the transaction: another transaction took it
+	nonce, err := cli.NonceAt(ctx, tt.from, nil)
+	if err != nil {
+		err = fmt.Errorf("nonce at: %w", err)
+		return
+	}
+	if nonce > tt.tx.Nonce() {
+		// the receipt may have been written in between
+		if _, err = cli.TransactionReceipt(ctx, tt.hash); err == nil {
+			return
+		}
+		err = fmt.Errorf("%w: nonce %d of %s was used by another transaction", ErrTxDropped, tt.tx.Nonce(), tt.from.Hex())
+	}
+	return
+}
+
+func (ct *ConfirmationTracker) parseIfConfirmed(ctx context.Context, tt *trackedTx, receipt *types.Receipt) (ptx *ParsedTx, confirmed bool, err error) {
+	cli := ct.tp.cli
+	head, err := cli.BlockNumber(ctx)
+	if err != nil {
+		err = fmt.Errorf("block number: %w", err)
+		return
+	}
+	blockNumber := receipt.BlockNumber.Uint64()
+	if head < blockNumber+ct.opts.Confirmations-1 {
+		zlog.Debug("waiting for confirmations",
+			zap.String("txHash", tt.hash.Hex()),
+			zap.Uint64("block", blockNumber),
+			zap.Uint64("head", head))
+		return
+	}
+
+	ptx, err = ct.tp.parseTxByReceipt(ctx, receipt)
+	if errors.Is(err, ethereum.NotFound) {
+		// the block was reorged out after the receipt was read
+		err = nil
+		return
+	}
+	if err != nil {
+		return
+	}
+	confirmed = true
+	return
+}
@@ -0,0 +1,93 @@
+package uethereum
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfirmationTracker(t *testing.T) {
+	ctx := context.Background()
+	svc := &mockEthService{chainID: big.NewInt(1), nonce: 2, latestNonce: 2}
+	wc := newMockWalletClient(t, svc)
+	to := "0x9858EfFD232B4033E47d90003D41EC34EcaEda94"
+	ct := wc.ConfirmationTracker(ConfirmationOptions{
+		Confirmations:  3,
+		PollInterval:   10 * time.Millisecond,
+		DroppedTimeout: 50 * time.Millisecond,
+	})
+
+	t.Run("confirmed", func(t *testing.T) {
+		txHash, err := wc.TransferETH(ctx, to, big.NewInt(1), 21000, big.NewInt(100))
+		assert.NoError(t, err)
+		svc.mine(svc.sent[len(svc.sent)-1], 10, types.ReceiptStatusSuccessful)
+
+		// 1 of 3 confirmations
+		waitCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+		defer cancel()
+		_, err = ct.Wait(waitCtx, txHash)
+		assert.Error(t, err)
+
+		go func() {
+			time.Sleep(30 * time.Millisecond)
+			svc.mu.Lock()
+			svc.head = 12
+			svc.mu.Unlock()
+		}()
+		ptx, err := ct.Wait(ctx, txHash)
+		assert.NoError(t, err)
+		assert.Equal(t, txHash, ptx.TxHash)
+		assert.Equal(t, big.NewInt(10), ptx.Block)
+		assert.Equal(t, "success", ptx.Status)
+		assert.Equal(t, wc.account.Hex(), ptx.From)
+		assert.Equal(t, big.NewInt(100*21000), ptx.Fee)
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		txHash, err := wc.TransferETH(ctx, to, big.NewInt(1), 21000, big.NewInt(100))
+		assert.NoError(t, err)
+		svc.mine(svc.sent[len(svc.sent)-1], 20, types.ReceiptStatusFailed)
+		svc.mu.Lock()
+		svc.head = 30
+		svc.mu.Unlock()
+
+		ptx, err := ct.Wait(ctx, txHash)
+		assert.NoError(t, err)
+		assert.Equal(t, "fail", ptx.Status)
+	})
+
+	t.Run("nonce used by another transaction", func(t *testing.T) {
+		txHash, err := wc.TransferETH(ctx, to, big.NewInt(1), 21000, big.NewInt(100))
+		assert.NoError(t, err)
+		svc.mu.Lock()
+		svc.latestNonce++
+		svc.mu.Unlock()
+
+		_, err = ct.Wait(ctx, txHash)
+		assert.ErrorIs(t, err, ErrTxDropped)
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		_, err := ct.Wait(ctx, common.Hash{1}.Hex())
+		assert.ErrorIs(t, err, ErrTxDropped)
+	})
+}
+
+func TestConfirmationTrackerPartialOptions(t *testing.T) {
+	ct := NewConfirmationTracker(nil, ConfirmationOptions{Confirmations: 3})
+	assert.Equal(t, ConfirmationOptions{
+		Confirmations:  3,
+		PollInterval:   DefaultConfirmationOptions.PollInterval,
+		DroppedTimeout: DefaultConfirmationOptions.DroppedTimeout,
+	}, ct.opts)
+
+	ct = NewConfirmationTracker(nil, ConfirmationOptions{PollInterval: time.Second})
+	assert.Equal(t, uint64(1), ct.opts.Confirmations)
+	assert.Equal(t, time.Second, ct.opts.PollInterval)
+	assert.Equal(t, DefaultConfirmationOptions.DroppedTimeout, ct.opts.DroppedTimeout)
+}
@@ -7,6 +7,31 @@ import (
 	"github.com/ethereum/go-ethereum/accounts/abi"
 )
 
+// BIP-0044 derivation paths, the coin types are registered in https://github.com/satoshilabs/slips/blob/master/slip-0044.md
+const (
+	// DerivationPathFormat is the BIP-0044 path used by MetaMask, the placeholder is the address index.
+	DerivationPathFormat = "m/44'/60'/0'/0/%d"
+
+	// AccountPathFormat is the BIP-0044 account level path, the placeholder is the account index.
+	// Extended public keys exported at this level derive addresses with the relative path "0/{addressIndex}".
+	AccountPathFormat = "m/44'/60'/%d'"
+)
+
+// EIP-1559 fee suggestion from eth_feeHistory: https://eips.ethereum.org/EIPS/eip-1559
+const (
+	// FeeHistoryBlocks is the number of latest blocks read by eth_feeHistory.
+	FeeHistoryBlocks = 20
+	// FeeHistoryRewardPercentile is the percentile of the priority fees paid in each block.
+	FeeHistoryRewardPercentile = 50
+	// BaseFeeMultiplier is applied to the next base fee in the max fee,
+	// the base fee rises at most 12.5% per block so 2x stays valid for 6 full blocks.
+	BaseFeeMultiplier = 2
+)
+
+// ReplacementFeeBumpPercent is the minimum fee bump of a replacement transaction with the same nonce,
+// the default price bump of the geth txpool: https://github.com/ethereum/go-ethereum/blob/master/core/txpool/legacypool/legacypool.go
+const ReplacementFeeBumpPercent = 10
+
 var (
 	GweiPerETH = big.NewInt(1000000000)                               // 1 ETH = 1,000,000,000 Gwei
 	WeiPerETH  = new(big.Int).Mul(GweiPerETH, big.NewInt(1000000000)) // 1 ETH = 1,000,000,000,000,000,000 Wei
@@ -14,12 +39,21 @@ var (
 
 var erc20ABI abi.ABI
 
+// erc1155ABI has the transfer events of ERC-1155: https://eips.ethereum.org/EIPS/eip-1155
+var erc1155ABI abi.ABI
+
 func init() {
 	parsedABI, err := abi.JSON(strings.NewReader(erc20ABIJson))
 	if err != nil {
 		panic("parse erc20 abi json" + err.Error())
 	}
 	erc20ABI = parsedABI
+
+	parsedABI, err = abi.JSON(strings.NewReader(erc1155ABIJson))
+	if err != nil {
+		panic("parse erc1155 abi json" + err.Error())
+	}
+	erc1155ABI = parsedABI
 }
 
 func GetERC20ABI() abi.ABI {
@@ -248,3 +282,70 @@ const erc20ABIJson = `[
     "type": "event"
   }
 ]`
+
+const erc1155ABIJson = `[
+  {
+    "anonymous": false,
+    "inputs": [
+      {
+        "indexed": true,
+        "name": "operator",
+        "type": "address"
+      },
+      {
+        "indexed": true,
+        "name": "from",
+        "type": "address"
+      },
+      {
+        "indexed": true,
+        "name": "to",
+        "type": "address"
+      },
+      {
+        "indexed": false,
+        "name": "id",
+        "type": "uint256"
+      },
+      {
+        "indexed": false,
+        "name": "value",
+        "type": "uint256"
+      }
+    ],
+    "name": "TransferSingle",
+    "type": "event"
+  },
+  {
+    "anonymous": false,
+    "inputs": [
+      {
+        "indexed": true,
+        "name": "operator",
+        "type": "address"
+      },
+      {
+        "indexed": true,
+        "name": "from",
+        "type": "address"
+      },
+      {
+        "indexed": true,
+        "name": "to",
+        "type": "address"
+      },
+      {
+        "indexed": false,
+        "name": "ids",
+        "type": "uint256[]"
+      },
+      {
+        "indexed": false,
+        "name": "values",
+        "type": "uint256[]"
+      }
+    ],
+    "name": "TransferBatch",
+    "type": "event"
+  }
+]`
@@ -0,0 +1,221 @@
+package uethereum
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"time"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// Gas oracle based on eth_feeHistory, it works on every EVM chain supporting EIP-1559 (Ethereum, Arbitrum, ...).
+// https://docs.alchemy.com/docs/how-to-build-a-gas-fee-estimator-using-eip-1559
+
+// GasSpeed is the speed tier of a suggested fee.
+type GasSpeed int
+
+const (
+	GasSpeedSlow GasSpeed = iota
+	GasSpeedStandard
+	GasSpeedFast
+)
+
+func (s GasSpeed) String() string {
+	switch s {
+	case GasSpeedSlow:
+		return "slow"
+	case GasSpeedStandard:
+		return "standard"
+	case GasSpeedFast:
+		return "fast"
+	}
+	return fmt.Sprintf("GasSpeed(%d)", int(s))
+}
+
+var ErrBaseFeeAboveCap = errors.New("base fee exceeds the max fee cap")
+
+// GasOracleOptions configures a GasOracle.
+type GasOracleOptions struct {
+	// Blocks is the number of latest blocks read by eth_feeHistory.
+	Blocks uint64
+	// RewardPercentiles are the percentiles of the priority fees paid in each block, for the slow, standard and fast tiers.
+	RewardPercentiles [3]float64
+	// BaseFeeMultiplier is applied to the next base fee in the max fee.
+	BaseFeeMultiplier int64
+	// BlockTime is the average block time of the chain, used to estimate the inclusion time.
+	BlockTime time.Duration
+	// MaxFeeCap is optional, the suggested max fee never exceeds it.
+	MaxFeeCap *big.Int
+}
+
+// DefaultGasOracleOptions are the options for Ethereum mainnet, used when NewGasOracle is called without options.
+var DefaultGasOracleOptions = GasOracleOptions{
+	Blocks:            FeeHistoryBlocks,
+	RewardPercentiles: [3]float64{10, FeeHistoryRewardPercentile, 90},
+	BaseFeeMultiplier: BaseFeeMultiplier,
+	BlockTime:         12 * time.Second,
+}
+
+// GasFee is the suggested fee of one speed tier, in wei per gas.
+type GasFee struct {
+	BaseFee       *big.Int // base fee of the next block
+	PriorityFee   *big.Int
+	MaxFee        *big.Int
+	EstimatedWait time.Duration // estimated time until the transaction is included
+}
+
+func (f GasFee) DynamicFee() DynamicFee {
+	return DynamicFee{
+		MaxFeePerGas:         new(big.Int).Set(f.MaxFee),
+		MaxPriorityFeePerGas: new(big.Int).Set(f.PriorityFee),
+	}
+}
+
+type GasFees struct {
+	Slow     GasFee
+	Standard GasFee
+	Fast     GasFee
+}
+
+func (f GasFees) Tier(speed GasSpeed) GasFee {
+	switch speed {
+	case GasSpeedSlow:
+		return f.Slow
+	case GasSpeedFast:
+		return f.Fast
+	}
+	return f.Standard
+}
+
+type GasOracle struct {
+	cli  *ethclient.Client
+	opts GasOracleOptions
+}
+
+// NewGasOracle creates a gas oracle, the zero fields of the options are set from DefaultGasOracleOptions.
+func NewGasOracle(cli *ethclient.Client, optsOption ...GasOracleOptions) *GasOracle {
+	opts := DefaultGasOracleOptions
+	if len(optsOption) > 0 {
+		opts = optsOption[0]
+	}
+	if opts.Blocks == 0 {
+		opts.Blocks = DefaultGasOracleOptions.Blocks
+	}
+	if opts.RewardPercentiles == [3]float64{} {
+		opts.RewardPercentiles = DefaultGasOracleOptions.RewardPercentiles
+	}
+	if opts.BaseFeeMultiplier == 0 {
+		opts.BaseFeeMultiplier = DefaultGasOracleOptions.BaseFeeMultiplier
+	}
+	if opts.BlockTime == 0 {
+		opts.BlockTime = DefaultGasOracleOptions.BlockTime
+	}
+	return &GasOracle{cli: cli, opts: opts}
+}
+
+// GasOracle returns a gas oracle of the chain the WalletClient is connected to.
+func (wc *WalletClient) GasOracle(optsOption ...GasOracleOptions) *GasOracle {
+	return NewGasOracle(wc.cli, optsOption...)
+}
+
+// SuggestFees suggests the fees of all speed tiers.
+// The priority fee of a tier is the median of its reward percentile over the blocks,
+// and the max fee is BaseFeeMultiplier * next base fee + priority fee, limited by MaxFeeCap.
+// The wait is estimated from the share of the blocks that included transactions paying less than the priority fee.
+func (o *GasOracle) SuggestFees(ctx context.Context) (fees GasFees, err error) {
+	history, err := o.cli.FeeHistory(ctx, o.opts.Blocks, nil, o.opts.RewardPercentiles[:])
+	if err != nil {
+		err = fmt.Errorf("fee history: %w", err)
+		return
+	}
+	if len(history.BaseFee) == 0 {
+		err = errors.New("fee history: no base fee, the chain does not support EIP-1559")
+		return
+	}
+	// BaseFee contains the base fee of the next block after the newest block
+	nextBaseFee := history.BaseFee[len(history.BaseFee)-1]
+	if o.opts.MaxFeeCap != nil && nextBaseFee.Cmp(o.opts.MaxFeeCap) > 0 {
+		err = fmt.Errorf("%w: base fee %s, cap %s", ErrBaseFeeAboveCap, nextBaseFee, o.opts.MaxFeeCap)
+		return
+	}
+
+	// rewards of each tier, only the blocks with transactions have rewards
+	var tierRewards [3][]*big.Int
+	for _, reward := range history.Reward {
+		if len(reward) != len(tierRewards) || slices.Contains(reward, nil) {
+			continue
+		}
+		for i := range tierRewards {
+			tierRewards[i] = append(tierRewards[i], reward[i])
+		}
+	}
+
+	var tipCap *big.Int
+	tiers := make([]GasFee, len(tierRewards))
+	for i, rewards := range tierRewards {
+		var priorityFee *big.Int
+		if len(rewards) > 0 {
+			sorted := slices.SortedFunc(slices.Values(rewards), (*big.Int).Cmp)
+			priorityFee = new(big.Int).Set(sorted[len(sorted)/2])
+		} else {
+			// no transactions in the latest blocks, ask the node
+			if tipCap == nil {
+				if tipCap, err = o.cli.SuggestGasTipCap(ctx); err != nil {
+					err = fmt.Errorf("suggest gas tip cap: %w", err)
+					return
+				}
+			}
+			priorityFee = new(big.Int).Set(tipCap)
+		}
+
+		maxFee := new(big.Int).Mul(nextBaseFee, big.NewInt(o.opts.BaseFeeMultiplier))
+		maxFee.Add(maxFee, priorityFee)
+		if o.opts.MaxFeeCap != nil && maxFee.Cmp(o.opts.MaxFeeCap) > 0 {
+			maxFee.Set(o.opts.MaxFeeCap)
+			// only maxFee - baseFee is left to the priority fee
+			if left := new(big.Int).Sub(maxFee, nextBaseFee); priorityFee.Cmp(left) > 0 {
+				priorityFee = left
+			}
+		}
+
+		tiers[i] = GasFee{
+			BaseFee:       new(big.Int).Set(nextBaseFee),
+			PriorityFee:   priorityFee,
+			MaxFee:        maxFee,
+			EstimatedWait: o.estimateWait(priorityFee, tierRewards[0]),
+		}
+	}
+	fees = GasFees{Slow: tiers[GasSpeedSlow], Standard: tiers[GasSpeedStandard], Fast: tiers[GasSpeedFast]}
+	return
+}
+
+// SuggestFee suggests the fee of one speed tier, e.g. GasSpeedFast with MaxFeeCap: "fast, but never above X gwei".
+func (o *GasOracle) SuggestFee(ctx context.Context, speed GasSpeed) (fee DynamicFee, err error) {
+	fees, err := o.SuggestFees(ctx)
+	if err != nil {
+		return
+	}
+	fee = fees.Tier(speed).DynamicFee()
+	return
+}
+
+// estimateWait returns BlockTime / p, p is the share of the blocks whose lowest tier reward is covered by the priority fee.
+func (o *GasOracle) estimateWait(priorityFee *big.Int, lowestRewards []*big.Int) time.Duration {
+	if len(lowestRewards) == 0 {
+		return o.opts.BlockTime
+	}
+	covered := 0
+	for _, reward := range lowestRewards {
+		if priorityFee.Cmp(reward) >= 0 {
+			covered++
+		}
+	}
+	if covered == 0 {
+		// not included within the observed blocks
+		return o.opts.BlockTime * time.Duration(len(lowestRewards)+1)
+	}
+	return o.opts.BlockTime * time.Duration(len(lowestRewards)) / time.Duration(covered)
+}
@@ -0,0 +1,85 @@
+package uethereum
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGasOracle(t *testing.T) {
+	gwei := func(n float64) *big.Int {
+		v, _ := new(big.Float).Mul(big.NewFloat(n), big.NewFloat(1e9)).Int(nil)
+		return v
+	}
+	ctx := context.Background()
+	svc := &mockEthService{
+		chainID:  big.NewInt(1),
+		baseFees: []*big.Int{gwei(10), gwei(11), gwei(12), gwei(13)},
+		// the 10th and 90th percentiles are 1/5 and 9/5 of the 50th percentile
+		rewards: []*big.Int{gwei(3), gwei(1), gwei(2)},
+		tipCap:  gwei(5),
+	}
+	wc := newMockWalletClient(t, svc)
+
+	fees, err := wc.GasOracle().SuggestFees(ctx)
+	assert.NoError(t, err)
+	for _, tc := range []struct {
+		speed       GasSpeed
+		priorityFee *big.Int
+		maxFee      *big.Int
+		wait        time.Duration
+	}{
+		{GasSpeedSlow, gwei(0.4), gwei(26.4), 18 * time.Second},
+		{GasSpeedStandard, gwei(2), gwei(28), 12 * time.Second},
+		{GasSpeedFast, gwei(3.6), gwei(29.6), 12 * time.Second},
+	} {
+		t.Run(tc.speed.String(), func(t *testing.T) {
+			fee := fees.Tier(tc.speed)
+			assert.Equal(t, gwei(13), fee.BaseFee)
+			assert.Equal(t, tc.priorityFee, fee.PriorityFee)
+			assert.Equal(t, tc.maxFee, fee.MaxFee)
+			assert.Equal(t, tc.wait, fee.EstimatedWait)
+		})
+	}
+
+	t.Run("max fee cap", func(t *testing.T) {
+		opts := DefaultGasOracleOptions
+		opts.MaxFeeCap = gwei(20)
+		fee, err := wc.GasOracle(opts).SuggestFee(ctx, GasSpeedFast)
+		assert.NoError(t, err)
+		assert.Equal(t, gwei(20), fee.MaxFeePerGas)
+		assert.Equal(t, gwei(3.6), fee.MaxPriorityFeePerGas)
+
+		// the priority fee is limited to cap - base fee
+		opts.MaxFeeCap = gwei(14)
+		fee, err = wc.GasOracle(opts).SuggestFee(ctx, GasSpeedFast)
+		assert.NoError(t, err)
+		assert.Equal(t, gwei(14), fee.MaxFeePerGas)
+		assert.Equal(t, gwei(1), fee.MaxPriorityFeePerGas)
+
+		opts.MaxFeeCap = gwei(12)
+		_, err = wc.GasOracle(opts).SuggestFee(ctx, GasSpeedFast)
+		assert.ErrorIs(t, err, ErrBaseFeeAboveCap)
+	})
+
+	t.Run("partial options", func(t *testing.T) {
+		fees, err := wc.GasOracle(GasOracleOptions{MaxFeeCap: gwei(100)}).SuggestFees(ctx)
+		assert.NoError(t, err)
+		defaultFees, err := wc.GasOracle().SuggestFees(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, defaultFees, fees)
+		assert.Equal(t, 1, fees.Standard.MaxFee.Cmp(fees.Standard.BaseFee))
+	})
+
+	t.Run("empty blocks", func(t *testing.T) {
+		svc.rewards = nil
+		fees, err := wc.GasOracle().SuggestFees(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, gwei(5), fees.Slow.PriorityFee)
+		assert.Equal(t, gwei(5), fees.Fast.PriorityFee)
+		assert.Equal(t, 12*time.Second, fees.Fast.EstimatedWait)
+	})
+}
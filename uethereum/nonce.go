@@ -0,0 +1,140 @@
+package uethereum
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// PendingNonceReader reads the nonce of an account including the pending transactions, implemented by *ethclient.Client.
+type PendingNonceReader interface {
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+}
+
+// NonceManager hands out the nonces of one account in order, it is safe for concurrent use.
+// Share one NonceManager between all the WalletClients sending from the account, see WalletClient.SetNonceManager.
+//
+// Every nonce from Acquire must be finished by calling done with the result of the send:
+// the nonce of a failed send is handed out again before any new nonce, so no gap is left,
+// and the next Acquire resyncs with the pending nonce of the chain.
+type NonceManager struct {
+	cli     PendingNonceReader
+	account common.Address
+
+	mu       sync.Mutex
+	synced   bool                // next was read from the chain
+	stale    bool                // a send failed, resync before the next Acquire
+	next     uint64              // next new nonce
+	inflight map[uint64]struct{} // acquired, the send is not finished
+	released map[uint64]struct{} // the send failed, to be handed out again
+}
+
+func NewNonceManager(cli PendingNonceReader, account common.Address) *NonceManager {
+	return &NonceManager{
+		cli:      cli,
+		account:  account,
+		inflight: make(map[uint64]struct{}),
+		released: make(map[uint64]struct{}),
+	}
+}
+
+func (nm *NonceManager) Account() common.Address {
+	return nm.account
+}
+
+// Acquire returns the next nonce, done must be called with the error of sending the transaction, nil on success.
+func (nm *NonceManager) Acquire(ctx context.Context) (nonce uint64, done func(sendErr error), err error) {
+	nm.mu.Lock()
+	defer nm.mu.Unlock()
+
+	if !nm.synced || nm.stale {
+		if err = nm.sync(ctx); err != nil {
+			return
+		}
+	}
+	if len(nm.released) > 0 {
+		nonce = slices.Min(slices.Collect(maps.Keys(nm.released)))
+		delete(nm.released, nonce)
+	} else {
+		nonce = nm.next
+		nm.next++
+	}
+	nm.inflight[nonce] = struct{}{}
+
+	var once sync.Once
+	done = func(sendErr error) {
+		once.Do(func() {
+			nm.finish(nonce, sendErr)
+		})
+	}
+	return
+}
+
+func (nm *NonceManager) finish(nonce uint64, sendErr error) {
+	nm.mu.Lock()
+	defer nm.mu.Unlock()
+	delete(nm.inflight, nonce)
+	if sendErr == nil {
+		return
+	}
+	nm.released[nonce] = struct{}{}
+	// the nonce may have been used by another sender, e.g. "nonce too low"
+	nm.stale = true
+}
+
+// Resync reads the pending nonce of the chain, the nonces used by other senders are skipped.
+func (nm *NonceManager) Resync(ctx context.Context) error {
+	nm.mu.Lock()
+	defer nm.mu.Unlock()
+	return nm.sync(ctx)
+}
+
+func (nm *NonceManager) sync(ctx context.Context) error {
+	pendingNonce, err := nm.cli.PendingNonceAt(ctx, nm.account)
+	if err != nil {
+		return fmt.Errorf("pending nonce at: %w", err)
+	}
+	// the nonces below the pending nonce are used, do not hand them out again
+	for nonce := range nm.released {
+		if nonce < pendingNonce {
+			delete(nm.released, nonce)
+		}
+	}
+	// keep the nonces handed out locally, their transactions may not have reached this node yet
+	if !nm.synced || pendingNonce > nm.next {
+		nm.next = pendingNonce
+	}
+	nm.synced = true
+	nm.stale = false
+	return nil
+}
+
+// DetectGaps returns the nonces below the local next nonce that the chain is missing, in ascending order.
+// Call it when no send is in progress, a transaction just sent may not have reached the node yet.
+// The transactions after a gap are stuck until a transaction with the missing nonce is sent,
+// the released nonces are filled by the next sends.
+func (nm *NonceManager) DetectGaps(ctx context.Context) (gaps []uint64, err error) {
+	pendingNonce, err := nm.cli.PendingNonceAt(ctx, nm.account)
+	if err != nil {
+		err = fmt.Errorf("pending nonce at: %w", err)
+		return
+	}
+
+	nm.mu.Lock()
+	defer nm.mu.Unlock()
+	for nonce := range nm.released {
+		if nonce >= pendingNonce {
+			gaps = append(gaps, nonce)
+		}
+	}
+	// the transaction of the pending nonce was sent, but the chain does not have it: it was dropped
+	if _, ok := nm.inflight[pendingNonce]; !ok && pendingNonce < nm.next && !slices.Contains(gaps, pendingNonce) {
+		gaps = append(gaps, pendingNonce)
+	}
+	slices.Sort(gaps)
+	return
+}
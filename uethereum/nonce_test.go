@@ -0,0 +1,153 @@
+package uethereum
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeNonceReader struct {
+	pendingNonce atomic.Uint64
+	calls        atomic.Int64
+}
+
+func (r *fakeNonceReader) PendingNonceAt(context.Context, common.Address) (uint64, error) {
+	r.calls.Add(1)
+	return r.pendingNonce.Load(), nil
+}
+
+func TestNonceManager(t *testing.T) {
+	ctx := context.Background()
+	errSend := errors.New("send failed")
+
+	t.Run("concurrent", func(t *testing.T) {
+		reader := &fakeNonceReader{}
+		reader.pendingNonce.Store(5)
+		nm := NewNonceManager(reader, common.Address{})
+
+		var (
+			mu     sync.Mutex
+			nonces []uint64
+			wg     sync.WaitGroup
+		)
+		for range 50 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				nonce, done, err := nm.Acquire(ctx)
+				assert.NoError(t, err)
+				mu.Lock()
+				nonces = append(nonces, nonce)
+				mu.Unlock()
+				done(nil)
+			}()
+		}
+		wg.Wait()
+		slices.Sort(nonces)
+		for i, nonce := range nonces {
+			assert.Equal(t, uint64(5+i), nonce)
+		}
+		assert.Equal(t, int64(1), reader.calls.Load()) // synced once
+	})
+
+	t.Run("failed send", func(t *testing.T) {
+		reader := &fakeNonceReader{}
+		nm := NewNonceManager(reader, common.Address{})
+
+		n0, done0, _ := nm.Acquire(ctx)
+		n1, done1, _ := nm.Acquire(ctx)
+		n2, done2, _ := nm.Acquire(ctx)
+		assert.Equal(t, []uint64{0, 1, 2}, []uint64{n0, n1, n2})
+		done0(nil)
+		done1(errSend)
+		done2(nil)
+		reader.pendingNonce.Store(1)
+
+		gaps, err := nm.DetectGaps(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, []uint64{1}, gaps)
+
+		// the failed nonce is handed out again, then the new ones
+		n, done, err := nm.Acquire(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(1), n)
+		done(nil)
+		n, done, _ = nm.Acquire(ctx)
+		assert.Equal(t, uint64(3), n)
+		done(nil)
+		reader.pendingNonce.Store(4)
+
+		gaps, err = nm.DetectGaps(ctx)
+		assert.NoError(t, err)
+		assert.Empty(t, gaps)
+	})
+
+	t.Run("resync", func(t *testing.T) {
+		reader := &fakeNonceReader{}
+		nm := NewNonceManager(reader, common.Address{})
+		n, done, _ := nm.Acquire(ctx)
+		assert.Equal(t, uint64(0), n)
+
+		// another sender used the nonce, "nonce too low"
+		reader.pendingNonce.Store(10)
+		done(errSend)
+		n, _, _ = nm.Acquire(ctx)
+		assert.Equal(t, uint64(10), n)
+
+		reader.pendingNonce.Store(20)
+		assert.NoError(t, nm.Resync(ctx))
+		n, _, _ = nm.Acquire(ctx)
+		assert.Equal(t, uint64(20), n)
+	})
+
+	t.Run("dropped tx", func(t *testing.T) {
+		reader := &fakeNonceReader{}
+		nm := NewNonceManager(reader, common.Address{})
+		for range 3 {
+			_, done, _ := nm.Acquire(ctx)
+			done(nil)
+		}
+		// the tx of nonce 1 was dropped from the mempool
+		reader.pendingNonce.Store(1)
+		gaps, err := nm.DetectGaps(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, []uint64{1}, gaps)
+	})
+}
+
+func TestWalletClientNonceManager(t *testing.T) {
+	ctx := context.Background()
+	svc := &mockEthService{chainID: big.NewInt(1), nonce: 3}
+	wc := newMockWalletClient(t, svc)
+	assert.Error(t, wc.SetNonceManager(NewNonceManager(wc.cli, common.Address{})))
+	assert.NoError(t, wc.SetNonceManager(NewNonceManager(wc.cli, wc.account)))
+
+	fee := DynamicFee{MaxFeePerGas: big.NewInt(100), MaxPriorityFeePerGas: big.NewInt(1)}
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := wc.TransferETHDynamicFee(ctx, "0x9858EfFD232B4033E47d90003D41EC34EcaEda94", big.NewInt(1), 21000, fee)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	nonces := make([]uint64, 0, len(svc.sent))
+	for _, tx := range svc.sent {
+		nonces = append(nonces, tx.Nonce())
+	}
+	slices.Sort(nonces)
+	assert.Len(t, nonces, 20)
+	for i, nonce := range nonces {
+		assert.Equal(t, uint64(3+i), nonce)
+	}
+}
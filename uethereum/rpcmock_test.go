@@ -0,0 +1,302 @@
+package uethereum
+
+import (
+	"encoding/json"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/15ho/wallet-utils-go/signer"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockEthService is an in-process "eth" JSON-RPC namespace for the tests without a node.
+type mockEthService struct {
+	mu sync.Mutex
+
+	chainID     *big.Int
+	nonce       uint64     // pending nonce
+	latestNonce uint64     // nonce of the mined transactions
+	baseFees    []*big.Int // eth_feeHistory baseFeePerGas, including the next block
+	rewards     []*big.Int // eth_feeHistory 50th percentile reward of each block, other percentiles are scaled by p/50
+	tipCap      *big.Int   // eth_maxPriorityFeePerGas
+	gasPrice    *big.Int   // eth_gasPrice
+
+	sent     []*types.Transaction
+	mined    map[common.Hash]bool
+	head     uint64 // eth_blockNumber
+	receipts map[common.Hash]*types.Receipt
+	blockTxs map[uint64][]*types.Transaction // mined transactions of each block
+}
+
+type mockFeeHistory struct {
+	OldestBlock  *hexutil.Big     `json:"oldestBlock"`
+	Reward       [][]*hexutil.Big `json:"reward,omitempty"`
+	BaseFee      []*hexutil.Big   `json:"baseFeePerGas,omitempty"`
+	GasUsedRatio []float64        `json:"gasUsedRatio"`
+}
+
+func (s *mockEthService) ChainId() *hexutil.Big {
+	return (*hexutil.Big)(s.chainID)
+}
+
+func (s *mockEthService) GetTransactionCount(_ common.Address, block string) hexutil.Uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if block == "latest" {
+		return hexutil.Uint64(s.latestNonce)
+	}
+	return hexutil.Uint64(s.nonce)
+}
+
+func (s *mockEthService) BlockNumber() hexutil.Uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return hexutil.Uint64(s.head)
+}
+
+func (s *mockEthService) GetTransactionReceipt(hash common.Hash) *types.Receipt {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.receipts[hash]
+}
+
+func (s *mockEthService) GetBlockByHash(hash common.Hash, _ bool) *types.Header {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for blockNumber := range s.blockTxs {
+		if block := s.block(blockNumber); block.Hash() == hash {
+			return block.Header()
+		}
+	}
+	return nil
+}
+
+// GetBlockByNumber returns the blocks up to the head with their transactions, the blocks without mined transactions are empty.
+func (s *mockEthService) GetBlockByNumber(number rpc.BlockNumber, _ bool) (map[string]any, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	blockNumber, ok := s.blockNumber(number)
+	if !ok {
+		return nil, nil
+	}
+	block := s.block(blockNumber)
+	res, err := toJSONMap(block.Header())
+	if err != nil {
+		return nil, err
+	}
+	txs := make([]map[string]any, 0, len(block.Transactions()))
+	for i, tx := range block.Transactions() {
+		txJSON, err := toJSONMap(tx)
+		if err != nil {
+			return nil, err
+		}
+		txJSON["blockHash"], txJSON["blockNumber"], txJSON["transactionIndex"] = block.Hash(), hexutil.Uint64(blockNumber), hexutil.Uint(i)
+		txs = append(txs, txJSON)
+	}
+	res["transactions"], res["uncles"] = txs, []common.Hash{}
+	return res, nil
+}
+
+func (s *mockEthService) GetBlockReceipts(blockNrOrHash rpc.BlockNumberOrHash) []*types.Receipt {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	number, _ := blockNrOrHash.Number()
+	blockNumber, ok := s.blockNumber(number)
+	if !ok {
+		return nil
+	}
+	receipts := make([]*types.Receipt, 0, len(s.blockTxs[blockNumber]))
+	for _, tx := range s.blockTxs[blockNumber] {
+		receipts = append(receipts, s.receipts[tx.Hash()])
+	}
+	return receipts
+}
+
+func (s *mockEthService) blockNumber(number rpc.BlockNumber) (uint64, bool) {
+	if number < 0 {
+		return s.head, true
+	}
+	return uint64(number), uint64(number) <= s.head
+}
+
+// block builds the block of the given height, the receipts of its transactions are updated with its hash.
+func (s *mockEthService) block(blockNumber uint64) *types.Block {
+	header := &types.Header{
+		Number:     new(big.Int).SetUint64(blockNumber),
+		Time:       1700000000 + blockNumber*12,
+		Difficulty: new(big.Int),
+		BaseFee:    big.NewInt(params.GWei),
+	}
+	txs := s.blockTxs[blockNumber]
+	receipts := make([]*types.Receipt, 0, len(txs))
+	for _, tx := range txs {
+		receipts = append(receipts, s.receipts[tx.Hash()])
+	}
+	block := types.NewBlock(header, &types.Body{Transactions: txs}, receipts, new(listHasher))
+	for i, receipt := range receipts {
+		receipt.BlockHash, receipt.BlockNumber, receipt.TransactionIndex = block.Hash(), block.Number(), uint(i)
+	}
+	return block
+}
+
+// listHasher is the types.TrieHasher of the mock blocks, the roots are the hash of the concatenated items instead of
+// the trie roots, ethclient only checks whether they are empty.
+type listHasher struct {
+	data []byte
+}
+
+func (h *listHasher) Reset() {
+	h.data = h.data[:0]
+}
+
+func (h *listHasher) Update(key, value []byte) error {
+	h.data = append(append(h.data, key...), value...)
+	return nil
+}
+
+func (h *listHasher) Hash() common.Hash {
+	return crypto.Keccak256Hash(h.data)
+}
+
+// mine includes a sent transaction in the block at the given height, the head is moved up to the block.
+func (s *mockEthService) mine(tx *types.Transaction, blockNumber uint64, status uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	gasPrice := tx.GasPrice()
+	if tx.Type() == types.DynamicFeeTxType {
+		gasPrice = new(big.Int).Add(big.NewInt(params.GWei), tx.GasTipCap())
+		if gasPrice.Cmp(tx.GasFeeCap()) > 0 {
+			gasPrice = tx.GasFeeCap()
+		}
+	}
+	if s.mined == nil {
+		s.mined = make(map[common.Hash]bool)
+	}
+	if s.receipts == nil {
+		s.receipts = make(map[common.Hash]*types.Receipt)
+		s.blockTxs = make(map[uint64][]*types.Transaction)
+	}
+	s.mined[tx.Hash()] = true
+	s.receipts[tx.Hash()] = &types.Receipt{
+		Type:              tx.Type(),
+		Status:            status,
+		CumulativeGasUsed: tx.Gas(),
+		Logs:              []*types.Log{},
+		TxHash:            tx.Hash(),
+		GasUsed:           tx.Gas(),
+		EffectiveGasPrice: gasPrice,
+	}
+	if tx.To() == nil && status == types.ReceiptStatusSuccessful {
+		if sender, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx); err == nil {
+			s.receipts[tx.Hash()].ContractAddress = crypto.CreateAddress(sender, tx.Nonce())
+		}
+	}
+	s.blockTxs[blockNumber] = append(s.blockTxs[blockNumber], tx)
+	s.block(blockNumber)
+	s.head = max(s.head, blockNumber)
+	s.latestNonce = max(s.latestNonce, tx.Nonce()+1)
+}
+
+// toJSONMap converts v to a JSON object to add fields to.
+func toJSONMap(v any) (res map[string]any, err error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	err = json.Unmarshal(b, &res)
+	return
+}
+
+func (s *mockEthService) GasPrice() *hexutil.Big {
+	return (*hexutil.Big)(s.gasPrice)
+}
+
+func (s *mockEthService) MaxPriorityFeePerGas() *hexutil.Big {
+	return (*hexutil.Big)(s.tipCap)
+}
+
+func (s *mockEthService) FeeHistory(_ hexutil.Uint64, _ string, percentiles []float64) *mockFeeHistory {
+	res := &mockFeeHistory{OldestBlock: (*hexutil.Big)(big.NewInt(100))}
+	for _, baseFee := range s.baseFees {
+		res.BaseFee = append(res.BaseFee, (*hexutil.Big)(baseFee))
+	}
+	for _, reward := range s.rewards {
+		blockRewards := make([]*hexutil.Big, 0, len(percentiles))
+		for _, p := range percentiles {
+			r := new(big.Int).Mul(reward, big.NewInt(int64(p)))
+			blockRewards = append(blockRewards, (*hexutil.Big)(r.Div(r, big.NewInt(50))))
+		}
+		res.Reward = append(res.Reward, blockRewards)
+		res.GasUsedRatio = append(res.GasUsedRatio, 0.5)
+	}
+	return res
+}
+
+func (s *mockEthService) SendRawTransaction(data hexutil.Bytes) (common.Hash, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(data); err != nil {
+		return common.Hash{}, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sent = append(s.sent, tx)
+	if tx.Nonce() == s.nonce {
+		s.nonce++
+	}
+	return tx.Hash(), nil
+}
+
+// GetTransactionByHash returns the sent transactions, they are pending unless mined.
+func (s *mockEthService) GetTransactionByHash(hash common.Hash) (map[string]any, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, tx := range s.sent {
+		if tx.Hash() != hash {
+			continue
+		}
+		txJSON, err := tx.MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+		var res map[string]any
+		if err := json.Unmarshal(txJSON, &res); err != nil {
+			return nil, err
+		}
+		res["blockHash"], res["blockNumber"] = nil, nil
+		if s.mined[hash] {
+			res["blockHash"], res["blockNumber"] = common.Hash{1}, "0x1"
+		}
+		return res, nil
+	}
+	return nil, nil
+}
+
+// newMockWalletClient returns a WalletClient of a new account connected to the mock service.
+func newMockWalletClient(t *testing.T, svc *mockEthService) *WalletClient {
+	srv := rpc.NewServer()
+	assert.NoError(t, srv.RegisterName("eth", svc))
+	t.Cleanup(srv.Stop)
+
+	pk, err := crypto.GenerateKey()
+	assert.NoError(t, err)
+	return &WalletClient{
+		cli:     ethclient.NewClient(rpc.DialInProc(srv)),
+		signer:  signer.NewLocalSecp256k1Signer(pk),
+		account: crypto.PubkeyToAddress(pk.PublicKey),
+	}
+}
+
+// senderOf returns the sender of a transaction sent to the mock service.
+func senderOf(t *testing.T, tx *types.Transaction) common.Address {
+	sender, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	assert.NoError(t, err)
+	return sender
+}
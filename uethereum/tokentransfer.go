@@ -0,0 +1,152 @@
+package uethereum
+
+import (
+	"fmt"
+	"math/big"
+	"slices"
+
+	"github.com/15ho/wallet-utils-go/internal/zlog"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"go.uber.org/zap"
+)
+
+// token standards of ParsedTokenTransfer
+const (
+	TokenStandardERC20   = "ERC20"
+	TokenStandardERC721  = "ERC721"
+	TokenStandardERC1155 = "ERC1155"
+)
+
+// ParsedTokenTransfer is a token movement decoded from a transfer event of a transaction.
+type ParsedTokenTransfer struct {
+	Standard string   // ERC20, ERC721 or ERC1155
+	Contract string   // token contract address
+	From     string   // from address // zero address for mints
+	To       string   // to address // zero address for burns
+	Amount   *big.Int // token amount // nil for ERC721
+	TokenID  *big.Int // token id // nil for ERC20
+	LogIndex uint     // index of the event log in the block // the transfers of an ERC1155 TransferBatch share it
+}
+
+// topics of the transfer events, the ABIs are parsed after the package variables are initialized
+var (
+	transferEventID       = crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)")) // same signature for ERC-20 and ERC-721
+	transferSingleEventID = crypto.Keccak256Hash([]byte("TransferSingle(address,address,address,uint256,uint256)"))
+	transferBatchEventID  = crypto.Keccak256Hash([]byte("TransferBatch(address,address,address,uint256[],uint256[])"))
+)
+
+// parseTokenTransfers decodes the ERC-20, ERC-721 and ERC-1155 transfer events of the logs,
+// the logs with a transfer event signature that do not follow the standards are skipped.
+func parseTokenTransfers(logs []*types.Log) []*ParsedTokenTransfer {
+	var transfers []*ParsedTokenTransfer
+	slices.Values(logs)(func(log *types.Log) bool {
+		if len(log.Topics) == 0 {
+			return true
+		}
+		var (
+			parsed []*ParsedTokenTransfer
+			err    error
+		)
+		switch log.Topics[0] {
+		case transferEventID:
+			parsed, err = parseTransferEvent(log)
+		case transferSingleEventID:
+			parsed, err = parseTransferSingleEvent(log)
+		case transferBatchEventID:
+			parsed, err = parseTransferBatchEvent(log)
+		default:
+			return true
+		}
+		if err != nil {
+			zlog.Debug("skip token transfer log", zap.Error(err),
+				zap.String("contract", log.Address.Hex()), zap.Uint("logIndex", log.Index))
+			return true
+		}
+		transfers = append(transfers, parsed...)
+		return true
+	})
+	return transfers
+}
+
+// parseTransferEvent decodes Transfer(address indexed from, address indexed to, uint256 value) of ERC-20
+// and Transfer(address indexed from, address indexed to, uint256 indexed tokenId) of ERC-721.
+func parseTransferEvent(log *types.Log) ([]*ParsedTokenTransfer, error) {
+	transfer := &ParsedTokenTransfer{
+		Contract: log.Address.Hex(),
+		LogIndex: log.Index,
+	}
+	switch len(log.Topics) {
+	case 3:
+		values, err := erc20ABI.Unpack("Transfer", log.Data)
+		if err != nil {
+			return nil, fmt.Errorf("unpack erc20 transfer: %w", err)
+		}
+		transfer.Standard = TokenStandardERC20
+		transfer.Amount = values[0].(*big.Int)
+	case 4:
+		transfer.Standard = TokenStandardERC721
+		transfer.TokenID = log.Topics[3].Big()
+	default:
+		return nil, fmt.Errorf("transfer event with %d topics", len(log.Topics))
+	}
+	transfer.From = topicAddress(log.Topics[1]).Hex()
+	transfer.To = topicAddress(log.Topics[2]).Hex()
+	return []*ParsedTokenTransfer{transfer}, nil
+}
+
+// parseTransferSingleEvent decodes TransferSingle(address indexed operator, address indexed from, address indexed to, uint256 id, uint256 value).
+func parseTransferSingleEvent(log *types.Log) ([]*ParsedTokenTransfer, error) {
+	if len(log.Topics) != 4 {
+		return nil, fmt.Errorf("transfer single event with %d topics", len(log.Topics))
+	}
+	values, err := erc1155ABI.Unpack("TransferSingle", log.Data)
+	if err != nil {
+		return nil, fmt.Errorf("unpack erc1155 transfer single: %w", err)
+	}
+	return []*ParsedTokenTransfer{{
+		Standard: TokenStandardERC1155,
+		Contract: log.Address.Hex(),
+		From:     topicAddress(log.Topics[2]).Hex(),
+		To:       topicAddress(log.Topics[3]).Hex(),
+		TokenID:  values[0].(*big.Int),
+		Amount:   values[1].(*big.Int),
+		LogIndex: log.Index,
+	}}, nil
+}
+
+// parseTransferBatchEvent decodes TransferBatch(address indexed operator, address indexed from, address indexed to, uint256[] ids, uint256[] values),
+// one transfer per id.
+func parseTransferBatchEvent(log *types.Log) ([]*ParsedTokenTransfer, error) {
+	if len(log.Topics) != 4 {
+		return nil, fmt.Errorf("transfer batch event with %d topics", len(log.Topics))
+	}
+	values, err := erc1155ABI.Unpack("TransferBatch", log.Data)
+	if err != nil {
+		return nil, fmt.Errorf("unpack erc1155 transfer batch: %w", err)
+	}
+	ids, amounts := values[0].([]*big.Int), values[1].([]*big.Int)
+	if len(ids) != len(amounts) {
+		return nil, fmt.Errorf("transfer batch with %d ids and %d values", len(ids), len(amounts))
+	}
+	from, to := topicAddress(log.Topics[2]).Hex(), topicAddress(log.Topics[3]).Hex()
+	transfers := make([]*ParsedTokenTransfer, 0, len(ids))
+	slices.All(ids)(func(i int, id *big.Int) bool {
+		transfers = append(transfers, &ParsedTokenTransfer{
+			Standard: TokenStandardERC1155,
+			Contract: log.Address.Hex(),
+			From:     from,
+			To:       to,
+			TokenID:  id,
+			Amount:   amounts[i],
+			LogIndex: log.Index,
+		})
+		return true
+	})
+	return transfers, nil
+}
+
+func topicAddress(topic common.Hash) common.Address {
+	return common.BytesToAddress(topic.Bytes())
+}
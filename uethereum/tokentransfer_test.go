@@ -0,0 +1,52 @@
+package uethereum
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTokenTransfers(t *testing.T) {
+	assert.Equal(t, erc20ABI.Events["Transfer"].ID, transferEventID)
+	assert.Equal(t, erc1155ABI.Events["TransferSingle"].ID, transferSingleEventID)
+	assert.Equal(t, erc1155ABI.Events["TransferBatch"].ID, transferBatchEventID)
+
+	token := common.HexToAddress("0xdAC17F958D2ee523a2206206994597C13D831ec7")
+	operator := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	from := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	to := common.HexToAddress("0x9858EfFD232B4033E47d90003D41EC34EcaEda94")
+	topic := func(addr common.Address) common.Hash { return common.BytesToHash(addr.Bytes()) }
+
+	erc20Data, err := erc20ABI.Events["Transfer"].Inputs.NonIndexed().Pack(big.NewInt(1000))
+	assert.NoError(t, err)
+	singleData, err := erc1155ABI.Events["TransferSingle"].Inputs.NonIndexed().Pack(big.NewInt(7), big.NewInt(3))
+	assert.NoError(t, err)
+	batchData, err := erc1155ABI.Events["TransferBatch"].Inputs.NonIndexed().Pack(
+		[]*big.Int{big.NewInt(1), big.NewInt(2)}, []*big.Int{big.NewInt(10), big.NewInt(20)})
+	assert.NoError(t, err)
+
+	logs := []*types.Log{
+		{Address: token, Topics: []common.Hash{transferEventID, topic(from), topic(to)}, Data: erc20Data, Index: 4},
+		{Address: token, Topics: []common.Hash{transferEventID, topic(common.Address{}), topic(to), common.BigToHash(big.NewInt(42))}, Index: 5},
+		{Address: token, Topics: []common.Hash{transferSingleEventID, topic(operator), topic(from), topic(to)}, Data: singleData, Index: 6},
+		{Address: token, Topics: []common.Hash{transferBatchEventID, topic(operator), topic(from), topic(to)}, Data: batchData, Index: 7},
+		// not transfers
+		{Address: token, Topics: []common.Hash{erc20ABI.Events["Approval"].ID, topic(from), topic(to)}, Data: erc20Data, Index: 8},
+		{Address: token, Topics: []common.Hash{transferEventID, topic(from), topic(to)}, Data: []byte{1}, Index: 9},
+		{Address: token, Index: 10},
+	}
+
+	transfers := parseTokenTransfers(logs)
+	assert.Equal(t, []*ParsedTokenTransfer{
+		{Standard: TokenStandardERC20, Contract: token.Hex(), From: from.Hex(), To: to.Hex(), Amount: big.NewInt(1000), LogIndex: 4},
+		{Standard: TokenStandardERC721, Contract: token.Hex(), From: common.Address{}.Hex(), To: to.Hex(), TokenID: big.NewInt(42), LogIndex: 5},
+		{Standard: TokenStandardERC1155, Contract: token.Hex(), From: from.Hex(), To: to.Hex(), TokenID: big.NewInt(7), Amount: big.NewInt(3), LogIndex: 6},
+		{Standard: TokenStandardERC1155, Contract: token.Hex(), From: from.Hex(), To: to.Hex(), TokenID: big.NewInt(1), Amount: big.NewInt(10), LogIndex: 7},
+		{Standard: TokenStandardERC1155, Contract: token.Hex(), From: from.Hex(), To: to.Hex(), TokenID: big.NewInt(2), Amount: big.NewInt(20), LogIndex: 7},
+	}, transfers)
+
+	assert.Empty(t, parseTokenTransfers(nil))
+}
@@ -3,9 +3,11 @@ package uethereum
 import (
 	"context"
 	"fmt"
+	"iter"
 	"math/big"
 	"slices"
 
+	"github.com/15ho/wallet-utils-go/internal/blockrange"
 	"github.com/15ho/wallet-utils-go/internal/zlog"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
@@ -21,28 +23,32 @@ type ParsedLog struct {
 }
 
 type ParsedTx struct {
-	Block          *big.Int     // block height
-	Timestamp      int64        // block timestamp // milliseconds
-	TxHash         string       // transaction hash
-	Status         string       // transaction status // success or fail
-	From           string       // from address
-	To             string       // to address // wallet or contract address
-	Value          *big.Int     // transaction value
-	Fee            *big.Int     // transaction fee // = gas price * gas used
-	GasLimit       uint64       // transaction gas limit
-	GasUsed        uint64       // transaction gas used
-	GasPrice       *big.Int     // transaction gas price // static or dynamic // dynamic: min((base fee + max priority fee), max fee)
-	BaseFee        *big.Int     // transaction base fee
-	MaxPriorityFee *big.Int     // transaction max priority fee
-	MaxFee         *big.Int     // transaction max fee
-	InputData      string       // transaction input data // hex string
-	Logs           []*ParsedLog // transaction logs
-	Nonce          uint64
-	TxType         uint8 // transaction type // https://ethereum.org/developers/docs/transactions/#typed-transaction-envelope
+	Block              *big.Int               // block height
+	Timestamp          int64                  // block timestamp // milliseconds
+	TxHash             string                 // transaction hash
+	Status             string                 // transaction status // success or fail
+	From               string                 // from address
+	To                 string                 // to address // wallet or contract address
+	IsContractCreation bool                   // contract creation transaction // To is empty
+	ContractAddress    string                 // created contract address // empty for other transactions and failed creations
+	Value              *big.Int               // transaction value
+	Fee                *big.Int               // transaction fee // = gas price * gas used
+	GasLimit           uint64                 // transaction gas limit
+	GasUsed            uint64                 // transaction gas used
+	GasPrice           *big.Int               // transaction gas price // static or dynamic // dynamic: min((base fee + max priority fee), max fee)
+	BaseFee            *big.Int               // transaction base fee
+	MaxPriorityFee     *big.Int               // transaction max priority fee
+	MaxFee             *big.Int               // transaction max fee
+	InputData          string                 // transaction input data // hex string
+	Logs               []*ParsedLog           // transaction logs
+	TokenTransfers     []*ParsedTokenTransfer // decoded ERC20, ERC721 and ERC1155 transfer events
+	Nonce              uint64
+	TxType             uint8 // transaction type // https://ethereum.org/developers/docs/transactions/#typed-transaction-envelope
 }
 
 type TxParser struct {
-	cli *ethclient.Client
+	cli    *ethclient.Client
+	strict bool
 }
 
 func NewTxParser(endpoint string) (*TxParser, error) {
@@ -50,38 +56,116 @@ func NewTxParser(endpoint string) (*TxParser, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &TxParser{cli}, nil
+	return &TxParser{cli: cli}, nil
 }
 
-func (tp *TxParser) ParseBlock(ctx context.Context, blockNumber *big.Int) ([]*ParsedTx, error) {
+// ParsedBlock is a parsed block, the transactions failing to parse are in Failed.
+type ParsedBlock struct {
+	Block  *big.Int // block height
+	Txs    []*ParsedTx
+	Failed []*TxParseError
+}
+
+// TxParseError is listed in ParsedBlock.Failed, TxHash is the 0x hex transaction hash.
+type TxParseError = blockrange.TxParseError
+
+// SetStrict makes ParseBlock and ParseBlockRange return an error for a block with a transaction failing to parse,
+// wrapping its *TxParseError, instead of listing it in ParsedBlock.Failed.
+func (tp *TxParser) SetStrict(strict bool) {
+	tp.strict = strict
+}
+
+// ParseBlock parses the transactions of a block, nil blockNumber is the latest block.
+func (tp *TxParser) ParseBlock(ctx context.Context, blockNumber *big.Int) (*ParsedBlock, error) {
 	block, err := tp.cli.BlockByNumber(ctx, blockNumber)
 	if err != nil {
 		return nil, fmt.Errorf("get block by number(%v): %w", blockNumber, err)
 	}
 
-	bn := rpc.LatestBlockNumber
-	if blockNumber != nil {
-		bn = rpc.BlockNumber(blockNumber.Int64())
-	}
-
+	// the number of the fetched block, the latest block may have changed
+	bn := rpc.BlockNumber(block.Number().Int64())
 	receipts, err := tp.cli.BlockReceipts(ctx, rpc.BlockNumberOrHash{
 		BlockNumber: &bn,
 	})
 	if err != nil {
-		return nil, fmt.Errorf("get block receipts  by number(%s): %w", bn.String(), err)
+		return nil, fmt.Errorf("get block receipts by number(%s): %w", bn.String(), err)
+	}
+	if len(receipts) != len(block.Transactions()) {
+		return nil, fmt.Errorf("block %s has %d transactions but %d receipts", bn.String(), len(block.Transactions()), len(receipts))
 	}
 
-	parsedTxs := make([]*ParsedTx, 0, len(block.Transactions()))
+	pb := &ParsedBlock{
+		Block: block.Number(),
+		Txs:   make([]*ParsedTx, 0, len(block.Transactions())),
+	}
+	var strictErr error
 	slices.All(block.Transactions())(func(idx int, tx *types.Transaction) bool {
 		ptx, err := tp.parseTx(block.Header(), tx, receipts[idx])
 		if err != nil {
+			txErr := &TxParseError{Index: idx, TxHash: tx.Hash().Hex(), Err: err}
+			if tp.strict {
+				strictErr = fmt.Errorf("block %s: %w", bn.String(), txErr)
+				return false
+			}
 			zlog.Error("parseTxWithReceipt", zap.Error(err), zap.String("txHash", tx.Hash().Hex()))
+			pb.Failed = append(pb.Failed, txErr)
 			return true
 		}
-		parsedTxs = append(parsedTxs, ptx)
+		pb.Txs = append(pb.Txs, ptx)
 		return true
 	})
-	return parsedTxs, nil
+	if strictErr != nil {
+		return nil, strictErr
+	}
+	return pb, nil
+}
+
+// ParseBlockRange parses the blocks from startBlock to endBlock (inclusive), workersOption blocks at a time (8 by default),
+// and yields them in block order. A block failing to parse is yielded with its error and the range goes on,
+// the iteration stops with ctx.Err() when ctx is done.
+func (tp *TxParser) ParseBlockRange(ctx context.Context, startBlock, endBlock uint64, workersOption ...int) iter.Seq2[*ParsedBlock, error] {
+	var workers int
+	if len(workersOption) > 0 {
+		workers = workersOption[0]
+	}
+	return blockrange.Parse(ctx, startBlock, endBlock, workers, func(ctx context.Context, blockNumber uint64) (*ParsedBlock, error) {
+		bn := new(big.Int).SetUint64(blockNumber)
+		pb, err := tp.ParseBlock(ctx, bn)
+		if err != nil {
+			return &ParsedBlock{Block: bn}, err
+		}
+		return pb, nil
+	})
+}
+
+// ParseTransaction parses an included transaction with its receipt, the result is the same as the transaction's entry of ParseBlock.
+// ethereum.NotFound is returned for pending and unknown transactions.
+func (tp *TxParser) ParseTransaction(ctx context.Context, txHash string) (*ParsedTx, error) {
+	hash, err := hexutil.Decode(txHash)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tx hash: %w", err)
+	}
+	if len(hash) != common.HashLength {
+		return nil, fmt.Errorf("invalid tx hash: %d bytes", len(hash))
+	}
+	receipt, err := tp.cli.TransactionReceipt(ctx, common.BytesToHash(hash))
+	if err != nil {
+		return nil, fmt.Errorf("get transaction receipt(%s): %w", txHash, err)
+	}
+	return tp.parseTxByReceipt(ctx, receipt)
+}
+
+// parseTxByReceipt reads the transaction and the block header of a receipt.
+func (tp *TxParser) parseTxByReceipt(ctx context.Context, receipt *types.Receipt) (*ParsedTx, error) {
+	header, err := tp.cli.HeaderByHash(ctx, receipt.BlockHash)
+	if err != nil {
+		return nil, fmt.Errorf("get header by hash(%s): %w", receipt.BlockHash.Hex(), err)
+	}
+	tx, _, err := tp.cli.TransactionByHash(ctx, receipt.TxHash)
+	if err != nil {
+		return nil, fmt.Errorf("get transaction by hash(%s): %w", receipt.TxHash.Hex(), err)
+	}
+	return tp.parseTx(header, tx, receipt)
 }
 
 func (tp *TxParser) parseTx(blockHeader *types.Header, tx *types.Transaction, receipt *types.Receipt) (ptx *ParsedTx, err error) {
@@ -121,7 +205,6 @@ func (tp *TxParser) parseTx(blockHeader *types.Header, tx *types.Transaction, re
 		TxHash:         tx.Hash().Hex(),
 		Status:         txStatus,
 		From:           from.Hex(),
-		To:             tx.To().Hex(),
 		Value:          tx.Value(),
 		GasLimit:       tx.Gas(),
 		GasUsed:        receipt.GasUsed,
@@ -131,10 +214,21 @@ func (tp *TxParser) parseTx(blockHeader *types.Header, tx *types.Transaction, re
 		MaxFee:         tx.GasFeeCap(),
 		InputData:      hexutil.Encode(tx.Data()),
 		Logs:           logs,
+		TokenTransfers: parseTokenTransfers(receipt.Logs),
 		Nonce:          tx.Nonce(),
 		TxType:         tx.Type(),
 	}
 
+	if tx.To() != nil {
+		ptx.To = tx.To().Hex()
+	} else {
+		// the contract is not deployed when the transaction failed
+		ptx.IsContractCreation = true
+		if receipt.Status == types.ReceiptStatusSuccessful {
+			ptx.ContractAddress = receipt.ContractAddress.Hex()
+		}
+	}
+
 	ptx.Fee = new(big.Int).Mul(ptx.GasPrice, new(big.Int).SetUint64(ptx.GasUsed))
 
 	return
@@ -1,9 +1,14 @@
 package uethereum
 
 import (
+	"math/big"
 	"testing"
 
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -31,4 +36,150 @@ func TestTxParser(t *testing.T) {
 	}
 }
 
+func TestParseTransaction(t *testing.T) {
+	ctx := t.Context()
+	svc := &mockEthService{chainID: big.NewInt(1)}
+	wc := newMockWalletClient(t, svc)
+	tp := &TxParser{cli: wc.cli}
+
+	txHash, err := wc.TransferETH(ctx, "0x9858EfFD232B4033E47d90003D41EC34EcaEda94", big.NewInt(1), 21000, big.NewInt(100))
+	assert.NoError(t, err)
+	_, err = tp.ParseTransaction(ctx, txHash)
+	assert.ErrorIs(t, err, ethereum.NotFound) // pending
+
+	svc.mine(svc.sent[0], 7, types.ReceiptStatusSuccessful)
+	ptx, err := tp.ParseTransaction(ctx, txHash)
+	assert.NoError(t, err)
+	assert.Equal(t, txHash, ptx.TxHash)
+	assert.Equal(t, big.NewInt(7), ptx.Block)
+	assert.Equal(t, "success", ptx.Status)
+	assert.Equal(t, wc.account.Hex(), ptx.From)
+	assert.Equal(t, big.NewInt(1), ptx.Value)
+	assert.Equal(t, uint64(21000), ptx.GasUsed)
+
+	for _, invalid := range []string{txHash[2:], txHash[:len(txHash)-2], txHash + "00", "0xzz"} {
+		_, err = tp.ParseTransaction(ctx, invalid)
+		assert.ErrorContains(t, err, "invalid tx hash", invalid)
+	}
+}
+
+func TestParseContractCreation(t *testing.T) {
+	ctx := t.Context()
+	svc := &mockEthService{chainID: big.NewInt(1)}
+	wc := newMockWalletClient(t, svc)
+	tp := &TxParser{cli: wc.cli}
+
+	pk, err := crypto.GenerateKey()
+	assert.NoError(t, err)
+	deployer := crypto.PubkeyToAddress(pk.PublicKey)
+	for nonce := range uint64(2) {
+		tx, err := types.SignNewTx(pk, types.LatestSignerForChainID(svc.chainID), &types.DynamicFeeTx{
+			ChainID:   svc.chainID,
+			Nonce:     nonce,
+			GasTipCap: big.NewInt(params.GWei),
+			GasFeeCap: big.NewInt(2 * params.GWei),
+			Gas:       100000,
+			Data:      common.FromHex("0x6080604052"),
+		})
+		assert.NoError(t, err)
+		assert.NoError(t, wc.cli.SendTransaction(ctx, tx))
+	}
+	svc.mine(svc.sent[0], 3, types.ReceiptStatusSuccessful)
+	svc.mine(svc.sent[1], 3, types.ReceiptStatusFailed)
+
+	pb, err := tp.ParseBlock(ctx, big.NewInt(3))
+	assert.NoError(t, err)
+	if assert.Len(t, pb.Txs, 2) {
+		assert.True(t, pb.Txs[0].IsContractCreation)
+		assert.Empty(t, pb.Txs[0].To)
+		assert.Equal(t, crypto.CreateAddress(deployer, 0).Hex(), pb.Txs[0].ContractAddress)
+		assert.Equal(t, deployer.Hex(), pb.Txs[0].From)
+
+		// failed deployment
+		assert.True(t, pb.Txs[1].IsContractCreation)
+		assert.Empty(t, pb.Txs[1].ContractAddress)
+	}
+
+	// not a creation
+	txHash, err := wc.TransferETH(ctx, "0x9858EfFD232B4033E47d90003D41EC34EcaEda94", big.NewInt(1), 21000, big.NewInt(100))
+	assert.NoError(t, err)
+	svc.mine(svc.sent[2], 4, types.ReceiptStatusSuccessful)
+	ptx, err := tp.ParseTransaction(ctx, txHash)
+	assert.NoError(t, err)
+	assert.False(t, ptx.IsContractCreation)
+	assert.Equal(t, "0x9858EfFD232B4033E47d90003D41EC34EcaEda94", ptx.To)
+	assert.Empty(t, ptx.ContractAddress)
+}
+
+func TestParseBlockRange(t *testing.T) {
+	ctx := t.Context()
+	svc := &mockEthService{chainID: big.NewInt(1)}
+	wc := newMockWalletClient(t, svc)
+	tp := &TxParser{cli: wc.cli}
+	to := "0x9858EfFD232B4033E47d90003D41EC34EcaEda94"
+
+	for range 3 {
+		_, err := wc.TransferETH(ctx, to, big.NewInt(1), 21000, big.NewInt(100))
+		assert.NoError(t, err)
+	}
+	svc.mine(svc.sent[0], 11, types.ReceiptStatusSuccessful)
+	svc.mine(svc.sent[1], 13, types.ReceiptStatusSuccessful)
+	svc.mine(svc.sent[2], 13, types.ReceiptStatusFailed)
+
+	var blocks []int64
+	for pb, err := range tp.ParseBlockRange(ctx, 10, 14, 3) {
+		blocks = append(blocks, pb.Block.Int64())
+		switch pb.Block.Int64() {
+		case 11:
+			assert.NoError(t, err)
+			assert.Len(t, pb.Txs, 1)
+			assert.Equal(t, svc.sent[0].Hash().Hex(), pb.Txs[0].TxHash)
+		case 13:
+			assert.NoError(t, err)
+			assert.Len(t, pb.Txs, 2)
+			assert.Equal(t, "success", pb.Txs[0].Status)
+			assert.Equal(t, "fail", pb.Txs[1].Status)
+		case 14:
+			assert.Error(t, err) // above the head
+		default:
+			assert.NoError(t, err)
+			assert.Empty(t, pb.Txs)
+		}
+	}
+	assert.Equal(t, []int64{10, 11, 12, 13, 14}, blocks)
+}
+
+func TestParseBlockFailed(t *testing.T) {
+	ctx := t.Context()
+	svc := &mockEthService{chainID: big.NewInt(1)}
+	wc := newMockWalletClient(t, svc)
+	tp := &TxParser{cli: wc.cli}
+
+	for range 2 {
+		_, err := wc.TransferETH(ctx, "0x9858EfFD232B4033E47d90003D41EC34EcaEda94", big.NewInt(1), 21000, big.NewInt(100))
+		assert.NoError(t, err)
+	}
+	svc.mine(svc.sent[0], 5, types.ReceiptStatusSuccessful)
+	svc.mine(svc.sent[1], 5, types.ReceiptStatusSuccessful)
+	svc.receipts[svc.sent[0].Hash()].TxHash = common.Hash{1} // does not match its transaction
+
+	pb, err := tp.ParseBlock(ctx, big.NewInt(5))
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(5), pb.Block)
+	assert.Len(t, pb.Txs, 1)
+	assert.Equal(t, svc.sent[1].Hash().Hex(), pb.Txs[0].TxHash)
+	if assert.Len(t, pb.Failed, 1) {
+		assert.Equal(t, 0, pb.Failed[0].Index)
+		assert.Equal(t, svc.sent[0].Hash().Hex(), pb.Failed[0].TxHash)
+		assert.Error(t, pb.Failed[0].Err)
+	}
+
+	tp.SetStrict(true)
+	_, err = tp.ParseBlock(ctx, big.NewInt(5))
+	var txErr *TxParseError
+	if assert.ErrorAs(t, err, &txErr) {
+		assert.Equal(t, svc.sent[0].Hash().Hex(), txErr.TxHash)
+	}
+}
+
 // TODO: add more tests
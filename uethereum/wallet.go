@@ -2,16 +2,21 @@ package uethereum
 
 import (
 	"context"
-	"crypto/ecdsa"
+	"errors"
 	"fmt"
 	"math/big"
 
+	"github.com/15ho/wallet-utils-go/bip32"
+	"github.com/15ho/wallet-utils-go/bip39"
+	"github.com/15ho/wallet-utils-go/keystore"
+	"github.com/15ho/wallet-utils-go/signer"
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/params"
 )
 
 func CreateWalletAccount() (privateKeyHex, address string, err error) {
@@ -25,6 +30,73 @@ func CreateWalletAccount() (privateKeyHex, address string, err error) {
 	return
 }
 
+// CreateWalletAccountFromMnemonic derives the account at m/44'/60'/0'/0/{accountIndex}, the same as MetaMask.
+// The passphrase is the optional BIP-0039 passphrase, pass "" if none.
+func CreateWalletAccountFromMnemonic(mnemonic, passphrase string, accountIndex uint32) (privateKeyHex, address string, err error) {
+	if err = bip39.Validate(mnemonic); err != nil {
+		err = fmt.Errorf("invalid mnemonic: %w", err)
+		return
+	}
+	seed := bip39.CreateSeedFromMnemonic(mnemonic, passphrase)
+	masterKey, err := bip32.NewMasterKey(seed)
+	if err != nil {
+		err = fmt.Errorf("new master key: %w", err)
+		return
+	}
+	key, err := masterKey.DerivePath(fmt.Sprintf(DerivationPathFormat, accountIndex))
+	if err != nil {
+		return
+	}
+	pk, err := key.ECPrivKey()
+	if err != nil {
+		return
+	}
+	privateKeyHex = hexutil.Encode(crypto.FromECDSA(pk))
+	address = crypto.PubkeyToAddress(pk.PublicKey).Hex()
+	return
+}
+
+// ExtendedPublicKeyFromMnemonic returns the extended public key (xpub) of the account at m/44'/60'/{account}'.
+// It can be given to a watch-only service to derive addresses with WalletAddressFromExtendedPublicKey.
+func ExtendedPublicKeyFromMnemonic(mnemonic, passphrase string, account uint32) (xpub string, err error) {
+	if err = bip39.Validate(mnemonic); err != nil {
+		err = fmt.Errorf("invalid mnemonic: %w", err)
+		return
+	}
+	seed := bip39.CreateSeedFromMnemonic(mnemonic, passphrase)
+	masterKey, err := bip32.NewMasterKey(seed)
+	if err != nil {
+		err = fmt.Errorf("new master key: %w", err)
+		return
+	}
+	key, err := masterKey.DerivePath(fmt.Sprintf(AccountPathFormat, account))
+	if err != nil {
+		return
+	}
+	xpub = key.Neuter().String()
+	return
+}
+
+// WalletAddressFromExtendedPublicKey derives the address at a non-hardened path relative to the extended public key,
+// no private key is needed. For an account level xpub (m/44'/60'/0'), the path "0/5" gives the address of m/44'/60'/0'/0/5.
+func WalletAddressFromExtendedPublicKey(xpub, path string) (address string, err error) {
+	key, err := bip32.ParseExtendedKey(xpub)
+	if err != nil {
+		err = fmt.Errorf("parse extended key: %w", err)
+		return
+	}
+	key, err = key.Neuter().DerivePath(path)
+	if err != nil {
+		return
+	}
+	pub, err := key.ECPubKey()
+	if err != nil {
+		return
+	}
+	address = crypto.PubkeyToAddress(*pub).Hex()
+	return
+}
+
 func WalletAddressFromPrivateKey(privateKeyHex string) (address string, err error) {
 	pk, err := crypto.ToECDSA(common.FromHex(privateKeyHex))
 	if err != nil {
@@ -37,28 +109,67 @@ func WalletAddressFromPrivateKey(privateKeyHex string) (address string, err erro
 type WalletClient struct {
 	cli *ethclient.Client
 
-	privateKey *ecdsa.PrivateKey
-	account    common.Address
+	signer  signer.Secp256k1Signer
+	account common.Address
+	nonces  *NonceManager // optional
 }
 
 func NewWalletClient(endpoint, privateKeyHex string) (*WalletClient, error) {
-	privateKey, err := crypto.ToECDSA(common.FromHex(privateKeyHex))
+	s, err := signer.NewLocalSecp256k1SignerFromHex(privateKeyHex)
 	if err != nil {
-		return nil, fmt.Errorf("invalid private key: %v", err)
+		return nil, err
 	}
+	return NewWalletClientFromSigner(endpoint, s)
+}
 
+// NewWalletClientFromSigner creates a WalletClient whose transactions are signed by s,
+// the private key is never touched by the WalletClient.
+func NewWalletClientFromSigner(endpoint string, s signer.Secp256k1Signer) (*WalletClient, error) {
 	cli, err := ethclient.Dial(endpoint)
 	if err != nil {
 		return nil, fmt.Errorf("dial: %v", err)
 	}
 
 	return &WalletClient{
-		cli:        cli,
-		privateKey: privateKey,
-		account:    crypto.PubkeyToAddress(privateKey.PublicKey),
+		cli:     cli,
+		signer:  s,
+		account: crypto.PubkeyToAddress(*s.PublicKey()),
 	}, nil
 }
 
+// SetNonceManager makes the WalletClient take the nonces of its transactions from nm instead of the pending nonce of the chain,
+// so concurrent sends from the account get different nonces. nm must belong to the account of the WalletClient.
+func (wc *WalletClient) SetNonceManager(nm *NonceManager) error {
+	if nm != nil && nm.Account() != wc.account {
+		return fmt.Errorf("nonce manager of %s, not %s", nm.Account(), wc.account)
+	}
+	wc.nonces = nm
+	return nil
+}
+
+// nextNonce returns the nonce of a new transaction, done must be called with the error of sending it.
+func (wc *WalletClient) nextNonce(ctx context.Context) (nonce uint64, done func(sendErr error), err error) {
+	if wc.nonces != nil {
+		return wc.nonces.Acquire(ctx)
+	}
+	nonce, err = wc.cli.PendingNonceAt(ctx, wc.account)
+	done = func(error) {}
+	return
+}
+
+// NewWalletClientFromKeystore opens a keystore v3 JSON file with the passphrase, see keystore.WriteKeyFile.
+func NewWalletClientFromKeystore(endpoint, keystorePath, passphrase string) (*WalletClient, error) {
+	privateKey, err := keystore.ReadKeyFile(keystorePath, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("open keystore: %w", err)
+	}
+	s, err := signer.NewLocalSecp256k1SignerFromBytes(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	return NewWalletClientFromSigner(endpoint, s)
+}
+
 func (wc *WalletClient) EstimateGasTransferETH(ctx context.Context, to string, amount *big.Int) (gas uint64, err error) {
 	toAddr := common.HexToAddress(to)
 	gas, err = wc.cli.EstimateGas(ctx, ethereum.CallMsg{
@@ -74,18 +185,19 @@ func (wc *WalletClient) SuggestGasPrice(ctx context.Context) (gasPrice *big.Int,
 }
 
 func (wc *WalletClient) TransferETH(ctx context.Context, to string, amount *big.Int, gasLimit uint64, gasPrice *big.Int) (txHash string, err error) {
-	nonce, err := wc.cli.PendingNonceAt(ctx, wc.account)
+	nonce, done, err := wc.nextNonce(ctx)
 	if err != nil {
 		err = fmt.Errorf("get nonce: %v", err)
 		return
 	}
+	defer func() { done(err) }()
 	chainID, err := wc.cli.ChainID(ctx)
 	if err != nil {
 		err = fmt.Errorf("get chain id: %v", err)
 		return
 	}
 	tx := types.NewTransaction(nonce, common.HexToAddress(to), amount, gasLimit, gasPrice, nil)
-	tx, err = types.SignTx(tx, types.NewEIP155Signer(chainID), wc.privateKey)
+	tx, err = signer.SignEVMTx(ctx, wc.signer, tx, types.NewEIP155Signer(chainID))
 	if err != nil {
 		err = fmt.Errorf("sign tx: %v", err)
 		return
@@ -99,6 +211,87 @@ func (wc *WalletClient) TransferETH(ctx context.Context, to string, amount *big.
 	return
 }
 
+// DynamicFee is the fee of an EIP-1559 (type 2) transaction, in wei per gas.
+// The effective gas price is min(MaxFeePerGas, baseFee + MaxPriorityFeePerGas).
+type DynamicFee struct {
+	MaxFeePerGas         *big.Int
+	MaxPriorityFeePerGas *big.Int
+}
+
+// SuggestDynamicFee suggests the EIP-1559 fee of the standard tier of the default GasOracle:
+// the priority fee is the median of the FeeHistoryRewardPercentile rewards of the latest FeeHistoryBlocks blocks,
+// and the max fee is BaseFeeMultiplier * next block base fee + priority fee.
+func (wc *WalletClient) SuggestDynamicFee(ctx context.Context) (fee DynamicFee, err error) {
+	return wc.GasOracle().SuggestFee(ctx, GasSpeedStandard)
+}
+
+// TransferETHDynamicFee sends ETH in an EIP-1559 transaction signed with the London signer.
+// The fee is derived by SuggestDynamicFee if not provided.
+func (wc *WalletClient) TransferETHDynamicFee(ctx context.Context, to string, amount *big.Int, gasLimit uint64, feeOption ...DynamicFee) (txHash string, err error) {
+	return wc.sendDynamicFeeTx(ctx, common.HexToAddress(to), amount, nil, gasLimit, feeOption...)
+}
+
+// TransferERC20TokenDynamicFee sends ERC20 tokens in an EIP-1559 transaction signed with the London signer.
+// The fee is derived by SuggestDynamicFee if not provided.
+func (wc *WalletClient) TransferERC20TokenDynamicFee(ctx context.Context, tokenContract, to string, amount *big.Int, gasLimit uint64, feeOption ...DynamicFee) (txHash string, err error) {
+	data, err := erc20ABI.Pack("transfer", common.HexToAddress(to), amount)
+	if err != nil {
+		err = fmt.Errorf("abi pack: %w", err)
+		return
+	}
+	return wc.sendDynamicFeeTx(ctx, common.HexToAddress(tokenContract), nil, data, gasLimit, feeOption...)
+}
+
+func (wc *WalletClient) sendDynamicFeeTx(ctx context.Context, to common.Address, value *big.Int, data []byte, gasLimit uint64, feeOption ...DynamicFee) (txHash string, err error) {
+	var fee DynamicFee
+	if len(feeOption) > 0 {
+		fee = feeOption[0]
+	} else if fee, err = wc.SuggestDynamicFee(ctx); err != nil {
+		return
+	}
+	if fee.MaxFeePerGas == nil || fee.MaxPriorityFeePerGas == nil {
+		err = errors.New("max fee per gas and max priority fee per gas are required")
+		return
+	}
+	if fee.MaxPriorityFeePerGas.Cmp(fee.MaxFeePerGas) > 0 {
+		err = fmt.Errorf("max priority fee per gas %s exceeds max fee per gas %s", fee.MaxPriorityFeePerGas, fee.MaxFeePerGas)
+		return
+	}
+
+	nonce, done, err := wc.nextNonce(ctx)
+	if err != nil {
+		err = fmt.Errorf("get nonce: %v", err)
+		return
+	}
+	defer func() { done(err) }()
+	chainID, err := wc.cli.ChainID(ctx)
+	if err != nil {
+		err = fmt.Errorf("get chain id: %v", err)
+		return
+	}
+	tx := types.NewTx(&types.DynamicFeeTx{
+		ChainID:   chainID,
+		Nonce:     nonce,
+		GasTipCap: fee.MaxPriorityFeePerGas,
+		GasFeeCap: fee.MaxFeePerGas,
+		Gas:       gasLimit,
+		To:        &to,
+		Value:     value,
+		Data:      data,
+	})
+	tx, err = signer.SignEVMTx(ctx, wc.signer, tx, types.NewLondonSigner(chainID))
+	if err != nil {
+		err = fmt.Errorf("sign tx: %v", err)
+		return
+	}
+	err = wc.cli.SendTransaction(ctx, tx)
+	if err != nil {
+		return
+	}
+	txHash = tx.Hash().Hex()
+	return
+}
+
 func (wc *WalletClient) GetETHBalance(ctx context.Context) (balance *big.Int, err error) {
 	return wc.cli.BalanceAt(ctx, wc.account, nil)
 }
@@ -124,11 +317,12 @@ func (wc *WalletClient) EstimateGasTransferERC20Token(ctx context.Context, token
 }
 
 func (wc *WalletClient) TransferERC20Token(ctx context.Context, tokenContract, to string, amount *big.Int, gasLimit uint64, gasPrice *big.Int) (txHash string, err error) {
-	nonce, err := wc.cli.PendingNonceAt(ctx, wc.account)
+	nonce, done, err := wc.nextNonce(ctx)
 	if err != nil {
 		err = fmt.Errorf("get nonce: %v", err)
 		return
 	}
+	defer func() { done(err) }()
 	chainID, err := wc.cli.ChainID(ctx)
 	if err != nil {
 		err = fmt.Errorf("get chain id: %v", err)
@@ -140,7 +334,7 @@ func (wc *WalletClient) TransferERC20Token(ctx context.Context, tokenContract, t
 		return
 	}
 	tx := types.NewTransaction(nonce, common.HexToAddress(tokenContract), nil, gasLimit, gasPrice, data)
-	tx, err = types.SignTx(tx, types.NewEIP155Signer(chainID), wc.privateKey)
+	tx, err = signer.SignEVMTx(ctx, wc.signer, tx, types.NewEIP155Signer(chainID))
 	if err != nil {
 		err = fmt.Errorf("sign tx: %v", err)
 		return
@@ -182,3 +376,133 @@ func (wc *WalletClient) GetERC20TokenBalanceByAddress(ctx context.Context, token
 	}
 	return
 }
+
+var ErrTxNotPending = errors.New("transaction is not pending")
+
+// ReplaceTransaction re-sends a pending transaction of the account with the same nonce and bumped fees, e.g. to speed it up.
+// The fees are raised by ReplacementFeeBumpPercent, or to the current suggestion if higher.
+// Legacy and EIP-1559 transactions are supported, the replacement has the same type.
+func (wc *WalletClient) ReplaceTransaction(ctx context.Context, txHash string) (newTxHash string, err error) {
+	return wc.replaceTransaction(ctx, txHash, false)
+}
+
+// CancelTransaction replaces a pending transaction of the account with a zero value transfer to itself,
+// with the same nonce and bumped fees as ReplaceTransaction.
+func (wc *WalletClient) CancelTransaction(ctx context.Context, txHash string) (newTxHash string, err error) {
+	return wc.replaceTransaction(ctx, txHash, true)
+}
+
+func (wc *WalletClient) replaceTransaction(ctx context.Context, txHash string, cancel bool) (newTxHash string, err error) {
+	tx, isPending, err := wc.cli.TransactionByHash(ctx, common.HexToHash(txHash))
+	if err != nil {
+		err = fmt.Errorf("get tx: %w", err)
+		return
+	}
+	if !isPending {
+		err = fmt.Errorf("%w: %s", ErrTxNotPending, txHash)
+		return
+	}
+	chainID, err := wc.cli.ChainID(ctx)
+	if err != nil {
+		err = fmt.Errorf("get chain id: %v", err)
+		return
+	}
+	txSigner := types.LatestSignerForChainID(chainID)
+	sender, err := types.Sender(txSigner, tx)
+	if err != nil {
+		err = fmt.Errorf("tx sender: %w", err)
+		return
+	}
+	if sender != wc.account {
+		err = fmt.Errorf("tx %s is sent by %s, not %s", txHash, sender, wc.account)
+		return
+	}
+
+	to, value, data, gas, accessList := tx.To(), tx.Value(), tx.Data(), tx.Gas(), tx.AccessList()
+	if cancel {
+		to, value, data, gas, accessList = &wc.account, new(big.Int), nil, params.TxGas, nil
+	}
+
+	var newTx *types.Transaction
+	switch tx.Type() {
+	case types.LegacyTxType, types.AccessListTxType:
+		suggestedGasPrice, serr := wc.cli.SuggestGasPrice(ctx)
+		if serr != nil {
+			err = fmt.Errorf("suggest gas price: %w", serr)
+			return
+		}
+		gasPrice := maxBigInt(bumpFee(tx.GasPrice()), suggestedGasPrice)
+		if tx.Type() == types.LegacyTxType {
+			newTx = types.NewTx(&types.LegacyTx{
+				Nonce:    tx.Nonce(),
+				GasPrice: gasPrice,
+				Gas:      gas,
+				To:       to,
+				Value:    value,
+				Data:     data,
+			})
+		} else {
+			newTx = types.NewTx(&types.AccessListTx{
+				ChainID:    chainID,
+				Nonce:      tx.Nonce(),
+				GasPrice:   gasPrice,
+				Gas:        gas,
+				To:         to,
+				Value:      value,
+				Data:       data,
+				AccessList: accessList,
+			})
+		}
+	case types.DynamicFeeTxType:
+		suggestedFee, serr := wc.SuggestDynamicFee(ctx)
+		if serr != nil {
+			err = serr
+			return
+		}
+		tipCap := maxBigInt(bumpFee(tx.GasTipCap()), suggestedFee.MaxPriorityFeePerGas)
+		feeCap := maxBigInt(bumpFee(tx.GasFeeCap()), suggestedFee.MaxFeePerGas, tipCap)
+		newTx = types.NewTx(&types.DynamicFeeTx{
+			ChainID:    chainID,
+			Nonce:      tx.Nonce(),
+			GasTipCap:  tipCap,
+			GasFeeCap:  feeCap,
+			Gas:        gas,
+			To:         to,
+			Value:      value,
+			Data:       data,
+			AccessList: accessList,
+		})
+	default:
+		err = fmt.Errorf("replace tx: unsupported tx type %d", tx.Type())
+		return
+	}
+
+	newTx, err = signer.SignEVMTx(ctx, wc.signer, newTx, txSigner)
+	if err != nil {
+		err = fmt.Errorf("sign tx: %v", err)
+		return
+	}
+	err = wc.cli.SendTransaction(ctx, newTx)
+	if err != nil {
+		return
+	}
+	newTxHash = newTx.Hash().Hex()
+	return
+}
+
+// bumpFee returns fee + ReplacementFeeBumpPercent%, rounded up.
+func bumpFee(fee *big.Int) *big.Int {
+	bumped := new(big.Int).Mul(fee, big.NewInt(100+ReplacementFeeBumpPercent))
+	bumped.Add(bumped, big.NewInt(99))
+	return bumped.Div(bumped, big.NewInt(100))
+}
+
+func maxBigInt(x *big.Int, ys ...*big.Int) *big.Int {
+	m := x
+	for _, y := range ys {
+		if y.Cmp(m) > 0 {
+			m = y
+		}
+	}
+	return new(big.Int).Set(m)
+}
@@ -1,9 +1,16 @@
 package uethereum
 
 import (
+	"context"
+	"fmt"
 	"math/big"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/15ho/wallet-utils-go/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -17,6 +24,72 @@ func TestCreateWalletAccount(t *testing.T) {
 	assert.Equal(t, address, address2)
 }
 
+func TestCreateWalletAccountFromMnemonic(t *testing.T) {
+	// addresses generated by MetaMask
+	mnemonic := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	for _, tc := range []struct {
+		accountIndex uint32
+		address      string
+	}{
+		{0, "0x9858EfFD232B4033E47d90003D41EC34EcaEda94"},
+		{1, "0x6Fac4D18c912343BF86fa7049364Dd4E424Ab9C0"},
+	} {
+		privateKeyHex, address, err := CreateWalletAccountFromMnemonic(mnemonic, "", tc.accountIndex)
+		assert.NoError(t, err)
+		assert.Equal(t, tc.address, address)
+
+		address2, err := WalletAddressFromPrivateKey(privateKeyHex)
+		assert.NoError(t, err)
+		assert.Equal(t, tc.address, address2)
+	}
+
+	_, address, err := CreateWalletAccountFromMnemonic(mnemonic, "TREZOR", 0)
+	assert.NoError(t, err)
+	assert.NotEqual(t, "0x9858EfFD232B4033E47d90003D41EC34EcaEda94", address)
+
+	_, _, err = CreateWalletAccountFromMnemonic("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon", "", 0)
+	assert.Error(t, err)
+}
+
+func TestNewWalletClientFromKeystore(t *testing.T) {
+	privateKeyHex, address, err := CreateWalletAccount()
+	assert.NoError(t, err)
+
+	keystorePath := filepath.Join(t.TempDir(), "acc1.json")
+	err = keystore.WriteKeyFile(keystorePath, common.FromHex(privateKeyHex), "p@ssw0rd", keystore.LightScryptOptions)
+	assert.NoError(t, err)
+
+	// http endpoints are dialed lazily
+	wc, err := NewWalletClientFromKeystore("http://127.0.0.1:8545", keystorePath, "p@ssw0rd")
+	assert.NoError(t, err)
+	assert.Equal(t, address, wc.account.Hex())
+
+	_, err = NewWalletClientFromKeystore("http://127.0.0.1:8545", keystorePath, "wrong")
+	assert.ErrorIs(t, err, keystore.ErrDecrypt)
+}
+
+func TestWalletAddressFromExtendedPublicKey(t *testing.T) {
+	mnemonic := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	xpub, err := ExtendedPublicKeyFromMnemonic(mnemonic, "", 0)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(xpub, "xpub"))
+
+	for i := uint32(0); i < 5; i++ {
+		_, expectAddress, err := CreateWalletAccountFromMnemonic(mnemonic, "", i)
+		assert.NoError(t, err)
+		address, err := WalletAddressFromExtendedPublicKey(xpub, fmt.Sprintf("0/%d", i))
+		assert.NoError(t, err)
+		assert.Equal(t, expectAddress, address)
+		t.Logf("address 0/%d: %s", i, address)
+	}
+
+	_, err = WalletAddressFromExtendedPublicKey(xpub, "0'/0")
+	assert.Error(t, err)
+
+	_, err = WalletAddressFromExtendedPublicKey("xpub-invalid", "0/0")
+	assert.Error(t, err)
+}
+
 func TestWalletClient(t *testing.T) {
 	wc, err := NewWalletClient(EthTestnet, Acc1PrivateKeyHex)
 	assert.NoError(t, err)
@@ -76,3 +149,133 @@ func TestWalletClient(t *testing.T) {
 		t.Logf("acc2 usdc balance: %s", balance)
 	})
 }
+
+func TestSuggestDynamicFee(t *testing.T) {
+	gwei := func(n int64) *big.Int { return new(big.Int).Mul(big.NewInt(n), GweiPerETH) }
+	ctx := context.Background()
+
+	svc := &mockEthService{
+		chainID:  big.NewInt(1),
+		baseFees: []*big.Int{gwei(10), gwei(11), gwei(12), gwei(13)},
+		rewards:  []*big.Int{gwei(3), gwei(1), gwei(2)},
+		tipCap:   gwei(5),
+	}
+	wc := newMockWalletClient(t, svc)
+	fee, err := wc.SuggestDynamicFee(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, gwei(2), fee.MaxPriorityFeePerGas) // median reward
+	assert.Equal(t, gwei(2*13+2), fee.MaxFeePerGas)    // 2 * next base fee + priority fee
+
+	// empty blocks, fall back to eth_maxPriorityFeePerGas
+	svc.rewards = nil
+	fee, err = wc.SuggestDynamicFee(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, gwei(5), fee.MaxPriorityFeePerGas)
+	assert.Equal(t, gwei(2*13+5), fee.MaxFeePerGas)
+}
+
+func TestTransferDynamicFee(t *testing.T) {
+	ctx := context.Background()
+	svc := &mockEthService{
+		chainID:  big.NewInt(1),
+		nonce:    7,
+		baseFees: []*big.Int{big.NewInt(100), big.NewInt(100)},
+		rewards:  []*big.Int{big.NewInt(10)},
+	}
+	wc := newMockWalletClient(t, svc)
+	to := "0x9858EfFD232B4033E47d90003D41EC34EcaEda94"
+
+	txHash, err := wc.TransferETHDynamicFee(ctx, to, big.NewInt(1), 21000)
+	assert.NoError(t, err)
+	tx := svc.sent[0]
+	assert.Equal(t, txHash, tx.Hash().Hex())
+	assert.Equal(t, uint8(types.DynamicFeeTxType), tx.Type())
+	assert.Equal(t, uint64(7), tx.Nonce())
+	assert.Equal(t, big.NewInt(10), tx.GasTipCap())
+	assert.Equal(t, big.NewInt(210), tx.GasFeeCap())
+	assert.Equal(t, wc.account, senderOf(t, tx))
+
+	fee := DynamicFee{MaxFeePerGas: big.NewInt(300), MaxPriorityFeePerGas: big.NewInt(20)}
+	_, err = wc.TransferERC20TokenDynamicFee(ctx, USDCTokenAddress, to, big.NewInt(1), 100000, fee)
+	assert.NoError(t, err)
+	tx = svc.sent[1]
+	assert.Equal(t, uint64(8), tx.Nonce())
+	assert.Equal(t, fee.MaxPriorityFeePerGas, tx.GasTipCap())
+	assert.Equal(t, fee.MaxFeePerGas, tx.GasFeeCap())
+	assert.Equal(t, common.HexToAddress(USDCTokenAddress), *tx.To())
+	assert.Equal(t, wc.account, senderOf(t, tx))
+
+	_, err = wc.TransferETHDynamicFee(ctx, to, big.NewInt(1), 21000, DynamicFee{MaxFeePerGas: big.NewInt(1), MaxPriorityFeePerGas: big.NewInt(2)})
+	assert.Error(t, err)
+}
+
+func TestReplaceTransaction(t *testing.T) {
+	ctx := context.Background()
+	svc := &mockEthService{
+		chainID:  big.NewInt(1),
+		nonce:    4,
+		baseFees: []*big.Int{big.NewInt(100), big.NewInt(100)},
+		rewards:  []*big.Int{big.NewInt(10)},
+		gasPrice: big.NewInt(100),
+		mined:    make(map[common.Hash]bool),
+	}
+	wc := newMockWalletClient(t, svc)
+	to := "0x9858EfFD232B4033E47d90003D41EC34EcaEda94"
+
+	t.Run("legacy", func(t *testing.T) {
+		txHash, err := wc.TransferETH(ctx, to, big.NewInt(1), 21000, big.NewInt(99))
+		assert.NoError(t, err)
+		newTxHash, err := wc.ReplaceTransaction(ctx, txHash)
+		assert.NoError(t, err)
+
+		tx, newTx := svc.sent[len(svc.sent)-2], svc.sent[len(svc.sent)-1]
+		assert.Equal(t, newTxHash, newTx.Hash().Hex())
+		assert.Equal(t, uint8(types.LegacyTxType), newTx.Type())
+		assert.Equal(t, tx.Nonce(), newTx.Nonce())
+		assert.Equal(t, big.NewInt(109), newTx.GasPrice()) // 99 * 1.1 rounded up
+		assert.Equal(t, tx.To(), newTx.To())
+		assert.Equal(t, tx.Value(), newTx.Value())
+		assert.Equal(t, wc.account, senderOf(t, newTx))
+
+		// the gas price of the network is higher than the bump
+		svc.gasPrice = big.NewInt(150)
+		newTxHash, err = wc.CancelTransaction(ctx, newTxHash)
+		assert.NoError(t, err)
+		cancelTx := svc.sent[len(svc.sent)-1]
+		assert.Equal(t, tx.Nonce(), cancelTx.Nonce())
+		assert.Equal(t, big.NewInt(150), cancelTx.GasPrice())
+		assert.Equal(t, wc.account, *cancelTx.To())
+		assert.Zero(t, cancelTx.Value().Sign())
+		assert.Equal(t, uint64(21000), cancelTx.Gas())
+	})
+
+	t.Run("dynamic fee", func(t *testing.T) {
+		fee := DynamicFee{MaxFeePerGas: big.NewInt(300), MaxPriorityFeePerGas: big.NewInt(20)}
+		txHash, err := wc.TransferERC20TokenDynamicFee(ctx, USDCTokenAddress, to, big.NewInt(1), 100000, fee)
+		assert.NoError(t, err)
+		tx := svc.sent[len(svc.sent)-1]
+
+		newTxHash, err := wc.CancelTransaction(ctx, txHash)
+		assert.NoError(t, err)
+		cancelTx := svc.sent[len(svc.sent)-1]
+		assert.Equal(t, newTxHash, cancelTx.Hash().Hex())
+		assert.Equal(t, uint8(types.DynamicFeeTxType), cancelTx.Type())
+		assert.Equal(t, tx.Nonce(), cancelTx.Nonce())
+		assert.Equal(t, big.NewInt(22), cancelTx.GasTipCap())
+		assert.Equal(t, big.NewInt(330), cancelTx.GasFeeCap())
+		assert.Equal(t, wc.account, *cancelTx.To())
+		assert.Empty(t, cancelTx.Data())
+		assert.Equal(t, wc.account, senderOf(t, cancelTx))
+
+		newTxHash, err = wc.ReplaceTransaction(ctx, newTxHash)
+		assert.NoError(t, err)
+		replaceTx := svc.sent[len(svc.sent)-1]
+		assert.Equal(t, big.NewInt(25), replaceTx.GasTipCap()) // 22 * 1.1 rounded up
+		assert.Equal(t, big.NewInt(363), replaceTx.GasFeeCap())
+		assert.Equal(t, wc.account, *replaceTx.To())
+
+		svc.mined[common.HexToHash(newTxHash)] = true
+		_, err = wc.ReplaceTransaction(ctx, newTxHash)
+		assert.ErrorIs(t, err, ErrTxNotPending)
+	})
+}
@@ -0,0 +1,570 @@
+package usolana
+
+import (
+	"bytes"
+	"cmp"
+	"compress/zlib"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"slices"
+	"strings"
+	"unicode"
+
+	bin "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+)
+
+// AnchorIDL decodes the instructions of an Anchor program with its IDL (interface description).
+// Both the IDL format of Anchor 0.30+ and the legacy format are supported.
+// https://www.anchor-lang.com/docs/basics/idl
+type AnchorIDL struct {
+	ProgramID    string // program address // base58 // empty when the IDL does not have it
+	Name         string // program name
+	instructions []anchorInstruction
+	types        map[string]*anchorTypeDef
+}
+
+type anchorInstruction struct {
+	name          string
+	discriminator []byte
+	accounts      []string // account names, the accounts of nested account groups are flattened as group.name
+	args          []anchorField
+}
+
+// anchorIDLJSON is the union of the IDL formats, the legacy format has no address nor discriminators
+// and its accounts are isMut/isSigner.
+type anchorIDLJSON struct {
+	Address  string `json:"address"`
+	Name     string `json:"name"`
+	Metadata struct {
+		Name    string `json:"name"`
+		Address string `json:"address"`
+	} `json:"metadata"`
+	Instructions []struct {
+		Name          string              `json:"name"`
+		Discriminator []int               `json:"discriminator"`
+		Accounts      []anchorAccountJSON `json:"accounts"`
+		Args          []anchorField       `json:"args"`
+	} `json:"instructions"`
+	Types []anchorTypeDef `json:"types"`
+}
+
+type anchorAccountJSON struct {
+	Name     string              `json:"name"`
+	Accounts []anchorAccountJSON `json:"accounts"` // account group
+}
+
+type anchorField struct {
+	Name string     `json:"name"`
+	Type anchorType `json:"type"`
+}
+
+type anchorTypeDef struct {
+	Name     string          `json:"name"`
+	Generics json.RawMessage `json:"generics"`
+	Type     struct {
+		Kind     string          `json:"kind"` // struct, enum or type (alias)
+		Fields   anchorFields    `json:"fields"`
+		Variants []anchorVariant `json:"variants"`
+		Alias    *anchorType     `json:"alias"`
+	} `json:"type"`
+}
+
+type anchorVariant struct {
+	Name   string       `json:"name"`
+	Fields anchorFields `json:"fields"`
+}
+
+// anchorFields are the named fields of a struct or an enum variant, or the types of a tuple.
+type anchorFields struct {
+	named []anchorField
+	tuple []anchorType
+}
+
+func (f *anchorFields) UnmarshalJSON(data []byte) error {
+	var items []json.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil {
+		return err
+	}
+	for _, item := range items {
+		var field struct {
+			Name *string         `json:"name"`
+			Type json.RawMessage `json:"type"`
+		}
+		if err := json.Unmarshal(item, &field); err == nil && field.Name != nil && field.Type != nil {
+			var named anchorField
+			if err := json.Unmarshal(item, &named); err != nil {
+				return err
+			}
+			f.named = append(f.named, named)
+			continue
+		}
+		var t anchorType
+		if err := json.Unmarshal(item, &t); err != nil {
+			return err
+		}
+		f.tuple = append(f.tuple, t)
+	}
+	if len(f.named) > 0 && len(f.tuple) > 0 {
+		return errors.New("mixed named and tuple fields")
+	}
+	return nil
+}
+
+// anchorType is a primitive type (u64, pubkey, string...) or a vec, option, coption, array or defined type.
+type anchorType struct {
+	primitive string
+	vec       *anchorType
+	option    *anchorType
+	coption   *anchorType
+	array     *anchorType
+	arrayLen  int
+	defined   string
+	generic   string // generic type parameter, generic array length or defined type with generic arguments // decoding it fails
+}
+
+func (t *anchorType) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &t.primitive); err == nil {
+		return nil
+	}
+	var obj struct {
+		Vec     *anchorType       `json:"vec"`
+		Option  *anchorType       `json:"option"`
+		COption *anchorType       `json:"coption"`
+		Array   []json.RawMessage `json:"array"`
+		Defined json.RawMessage   `json:"defined"`
+		Generic string            `json:"generic"`
+	}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return err
+	}
+	t.vec, t.option, t.coption = obj.Vec, obj.Option, obj.COption
+	switch {
+	case obj.Array != nil:
+		if len(obj.Array) != 2 {
+			return fmt.Errorf("invalid array type %s", data)
+		}
+		t.array = new(anchorType)
+		if err := json.Unmarshal(obj.Array[0], t.array); err != nil {
+			return err
+		}
+		if err := json.Unmarshal(obj.Array[1], &t.arrayLen); err != nil {
+			// 0.30+: "array": ["u8", {"generic": "N"}]
+			var length struct {
+				Generic string `json:"generic"`
+			}
+			if err := json.Unmarshal(obj.Array[1], &length); err != nil || length.Generic == "" {
+				return fmt.Errorf("unsupported array length %s", obj.Array[1])
+			}
+			t.generic = length.Generic
+		}
+		if t.arrayLen < 0 {
+			return fmt.Errorf("invalid array length %d", t.arrayLen)
+		}
+	case obj.Defined != nil:
+		// legacy: "defined": "Name", 0.30+: "defined": {"name": "Name"}
+		if err := json.Unmarshal(obj.Defined, &t.defined); err != nil {
+			var defined struct {
+				Name     string          `json:"name"`
+				Generics json.RawMessage `json:"generics"`
+			}
+			if err := json.Unmarshal(obj.Defined, &defined); err != nil {
+				return err
+			}
+			t.defined = defined.Name
+			if hasGenerics(defined.Generics) {
+				t.generic = defined.Name
+			}
+		}
+	case obj.Generic != "":
+		t.generic = obj.Generic
+	case t.vec == nil && t.option == nil && t.coption == nil:
+		return fmt.Errorf("unsupported type %s", data)
+	}
+	return nil
+}
+
+// ParseAnchorIDL parses an Anchor IDL JSON.
+func ParseAnchorIDL(data []byte) (*AnchorIDL, error) {
+	var idlJSON anchorIDLJSON
+	if err := json.Unmarshal(data, &idlJSON); err != nil {
+		return nil, fmt.Errorf("unmarshal anchor idl: %w", err)
+	}
+	idl := &AnchorIDL{
+		ProgramID: idlJSON.Address,
+		Name:      idlJSON.Metadata.Name,
+		types:     make(map[string]*anchorTypeDef, len(idlJSON.Types)),
+	}
+	if idl.ProgramID == "" {
+		idl.ProgramID = idlJSON.Metadata.Address
+	}
+	if idl.Name == "" {
+		idl.Name = idlJSON.Name
+	}
+	for i := range idlJSON.Types {
+		idl.types[idlJSON.Types[i].Name] = &idlJSON.Types[i]
+	}
+	for _, ins := range idlJSON.Instructions {
+		discriminator := make([]byte, 0, 8)
+		for _, b := range ins.Discriminator {
+			discriminator = append(discriminator, byte(b))
+		}
+		if len(discriminator) == 0 {
+			// legacy: sha256("global:<snake_case_name>")[:8]
+			sum := sha256.Sum256([]byte("global:" + toSnakeCase(ins.Name)))
+			discriminator = sum[:8]
+		}
+		idl.instructions = append(idl.instructions, anchorInstruction{
+			name:          ins.Name,
+			discriminator: discriminator,
+			accounts:      flattenAnchorAccounts("", ins.Accounts),
+			args:          ins.Args,
+		})
+	}
+	return idl, nil
+}
+
+// LoadAnchorIDL reads and parses a local Anchor IDL JSON file.
+func LoadAnchorIDL(path string) (*AnchorIDL, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read anchor idl: %w", err)
+	}
+	return ParseAnchorIDL(data)
+}
+
+// FetchAnchorIDL reads the IDL published on chain by an Anchor program (anchor idl init),
+// the IDL account is derived from the program id and holds the zlib compressed IDL JSON.
+func (tp *TxParser) FetchAnchorIDL(ctx context.Context, programID string) (*AnchorIDL, error) {
+	program, err := solana.PublicKeyFromBase58(programID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid program id: %w", err)
+	}
+	idlAddress, err := AnchorIDLAddress(program)
+	if err != nil {
+		return nil, err
+	}
+	res, err := tp.cli.GetAccountInfo(ctx, idlAddress)
+	if err != nil {
+		return nil, fmt.Errorf("get idl account(%s): %w", idlAddress, err)
+	}
+
+	// discriminator (8) + authority (32) + compressed data length (4) + compressed data
+	data := res.GetBinary()
+	if len(data) < 44 {
+		return nil, fmt.Errorf("invalid idl account data length %d", len(data))
+	}
+	dataLen := binary.LittleEndian.Uint32(data[40:44])
+	if uint64(dataLen) > uint64(len(data)-44) {
+		return nil, fmt.Errorf("idl data length %d exceeds the account data", dataLen)
+	}
+	zr, err := zlib.NewReader(bytes.NewReader(data[44 : 44+dataLen]))
+	if err != nil {
+		return nil, fmt.Errorf("decompress idl: %w", err)
+	}
+	defer zr.Close()
+	idlJSON, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, fmt.Errorf("decompress idl: %w", err)
+	}
+	idl, err := ParseAnchorIDL(idlJSON)
+	if err != nil {
+		return nil, err
+	}
+	if idl.ProgramID == "" {
+		idl.ProgramID = programID
+	}
+	return idl, nil
+}
+
+// AnchorIDLAddress returns the address of the on-chain IDL account of an Anchor program.
+func AnchorIDLAddress(programID solana.PublicKey) (solana.PublicKey, error) {
+	base, _, err := solana.FindProgramAddress(nil, programID)
+	if err != nil {
+		return solana.PublicKey{}, fmt.Errorf("find idl base address: %w", err)
+	}
+	return solana.CreateWithSeed(base, "anchor:idl", programID)
+}
+
+// RegisterAnchorIDL registers the IDL as the instruction parser of its program,
+// programIDOption overrides the program id of the IDL, e.g. for a deployment at another address.
+func (tp *TxParser) RegisterAnchorIDL(idl *AnchorIDL, programIDOption ...string) error {
+	programID := idl.ProgramID
+	if len(programIDOption) > 0 {
+		programID = programIDOption[0]
+	}
+	if programID == "" {
+		return errors.New("anchor idl has no program id")
+	}
+	return tp.RegisterInstructionParser(programID, idl.ParseInstruction)
+}
+
+// ParseInstruction is the InstructionParser of the program: the instruction is matched by its 8-byte discriminator,
+// Data is the map of the borsh decoded arguments by name and the accounts are named after the IDL.
+// TypeID is the index of the instruction in the IDL. The instructions not in the IDL are "Unknown" like the programs without parser.
+func (idl *AnchorIDL) ParseInstruction(tx *solana.Transaction, ins solana.CompiledInstruction) (ParsedInstruction, error) {
+	accounts, err := resolveInstructionAccounts(&tx.Message, ins.Accounts)
+	if err != nil {
+		return ParsedInstruction{}, err
+	}
+	idx := slices.IndexFunc(idl.instructions, func(idlIns anchorInstruction) bool {
+		return bytes.HasPrefix(ins.Data, idlIns.discriminator)
+	})
+	if idx < 0 {
+		return ParsedInstruction{
+			Name:     "Unknown",
+			Accounts: accounts,
+			Data:     ins.Data.String(), // base58
+		}, nil
+	}
+	idlIns := idl.instructions[idx]
+
+	slices.All(accounts)(func(i int, _ ParsedInstructionAccount) bool {
+		if i < len(idlIns.accounts) {
+			accounts[i].Name = idlIns.accounts[i]
+		}
+		return true
+	})
+	dec := bin.NewBorshDecoder(ins.Data[len(idlIns.discriminator):])
+	args := make(map[string]any, len(idlIns.args))
+	for _, arg := range idlIns.args {
+		if args[arg.Name], err = idl.decode(dec, &arg.Type, 0); err != nil {
+			// the named accounts are kept in the "Unknown" instruction of the TxParser
+			return ParsedInstruction{Accounts: accounts}, fmt.Errorf("decode %s arg %s: %w", idlIns.name, arg.Name, err)
+		}
+	}
+	return ParsedInstruction{
+		TypeID:   uint32(idx),
+		Name:     idlIns.name,
+		Accounts: accounts,
+		Data:     args,
+	}, nil
+}
+
+// maxAnchorTypeDepth limits the nesting of the decoded types, the IDLs fetched on chain are untrusted
+// and a recursive type, e.g. an alias of itself, would never stop.
+const maxAnchorTypeDepth = 64
+
+// decode decodes a borsh value: integers up to 64 bits and floats as Go numbers, 128 and 256 bits integers as *big.Int,
+// pubkeys as base58 strings, bytes as []byte, structs as map[string]any (tuples as []any),
+// enums as the variant name or a map of the variant name to its fields, a none option as nil.
+func (idl *AnchorIDL) decode(dec *bin.Decoder, t *anchorType, depth int) (any, error) {
+	if depth > maxAnchorTypeDepth {
+		return nil, fmt.Errorf("type nesting exceeds %d levels", maxAnchorTypeDepth)
+	}
+	if t.generic != "" {
+		return nil, fmt.Errorf("unsupported generic %s", t.generic)
+	}
+	switch {
+	case t.vec != nil:
+		n, err := dec.ReadLength()
+		if err != nil {
+			return nil, err
+		}
+		if n > dec.Remaining() {
+			return nil, fmt.Errorf("vec length %d exceeds the remaining %d bytes", n, dec.Remaining())
+		}
+		items := make([]any, 0, n)
+		for range n {
+			item, err := idl.decode(dec, t.vec, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		return items, nil
+	case t.array != nil:
+		if t.arrayLen > dec.Remaining() {
+			return nil, fmt.Errorf("array length %d exceeds the remaining %d bytes", t.arrayLen, dec.Remaining())
+		}
+		items := make([]any, 0, t.arrayLen)
+		for range t.arrayLen {
+			item, err := idl.decode(dec, t.array, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		return items, nil
+	case t.option != nil, t.coption != nil:
+		var (
+			some bool
+			err  error
+		)
+		if t.option != nil {
+			some, err = dec.ReadOption()
+		} else {
+			some, err = dec.ReadCOption()
+		}
+		if err != nil || !some {
+			return nil, err
+		}
+		return idl.decode(dec, cmp.Or(t.option, t.coption), depth+1)
+	case t.defined != "":
+		typeDef, ok := idl.types[t.defined]
+		if !ok {
+			return nil, fmt.Errorf("undefined type %s", t.defined)
+		}
+		if hasGenerics(typeDef.Generics) {
+			return nil, fmt.Errorf("unsupported generic type %s", t.defined)
+		}
+		return idl.decodeDefined(dec, typeDef, depth+1)
+	}
+	return decodePrimitive(dec, t.primitive)
+}
+
+func (idl *AnchorIDL) decodeDefined(dec *bin.Decoder, typeDef *anchorTypeDef, depth int) (any, error) {
+	switch typeDef.Type.Kind {
+	case "struct":
+		return idl.decodeFields(dec, &typeDef.Type.Fields, depth)
+	case "enum":
+		variantIdx, err := dec.ReadUint8()
+		if err != nil {
+			return nil, err
+		}
+		if int(variantIdx) >= len(typeDef.Type.Variants) {
+			return nil, fmt.Errorf("invalid variant %d of enum %s", variantIdx, typeDef.Name)
+		}
+		variant := &typeDef.Type.Variants[variantIdx]
+		if len(variant.Fields.named) == 0 && len(variant.Fields.tuple) == 0 {
+			return variant.Name, nil
+		}
+		fields, err := idl.decodeFields(dec, &variant.Fields, depth)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{variant.Name: fields}, nil
+	case "type":
+		if typeDef.Type.Alias == nil {
+			return nil, fmt.Errorf("type alias %s has no alias", typeDef.Name)
+		}
+		return idl.decode(dec, typeDef.Type.Alias, depth)
+	}
+	return nil, fmt.Errorf("unsupported kind %q of type %s", typeDef.Type.Kind, typeDef.Name)
+}
+
+func (idl *AnchorIDL) decodeFields(dec *bin.Decoder, fields *anchorFields, depth int) (any, error) {
+	if len(fields.tuple) > 0 {
+		items := make([]any, 0, len(fields.tuple))
+		for i := range fields.tuple {
+			item, err := idl.decode(dec, &fields.tuple[i], depth)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		return items, nil
+	}
+	values := make(map[string]any, len(fields.named))
+	for i := range fields.named {
+		field := &fields.named[i]
+		value, err := idl.decode(dec, &field.Type, depth)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", field.Name, err)
+		}
+		values[field.Name] = value
+	}
+	return values, nil
+}
+
+func decodePrimitive(dec *bin.Decoder, primitive string) (any, error) {
+	switch primitive {
+	case "bool":
+		return dec.ReadBool()
+	case "u8":
+		return dec.ReadUint8()
+	case "i8":
+		return dec.ReadInt8()
+	case "u16":
+		return dec.ReadUint16(bin.LE)
+	case "i16":
+		return dec.ReadInt16(bin.LE)
+	case "u32":
+		return dec.ReadUint32(bin.LE)
+	case "i32":
+		return dec.ReadInt32(bin.LE)
+	case "u64":
+		return dec.ReadUint64(bin.LE)
+	case "i64":
+		return dec.ReadInt64(bin.LE)
+	case "f32":
+		return dec.ReadFloat32(bin.LE)
+	case "f64":
+		return dec.ReadFloat64(bin.LE)
+	case "u128", "i128", "u256", "i256":
+		return decodeBigInt(dec, primitive)
+	case "string": // u32 length in borsh
+		return dec.ReadString()
+	case "bytes":
+		b, err := dec.ReadByteSlice()
+		return slices.Clone(b), err
+	case "pubkey", "publicKey":
+		b, err := dec.ReadBytes(solana.PublicKeyLength)
+		if err != nil {
+			return nil, err
+		}
+		return solana.PublicKeyFromBytes(b).String(), nil
+	}
+	return nil, fmt.Errorf("unsupported type %q", primitive)
+}
+
+// decodeBigInt decodes a little-endian 128 or 256 bits integer, the signed ones are two's complement.
+func decodeBigInt(dec *bin.Decoder, primitive string) (*big.Int, error) {
+	size := 16
+	if strings.HasSuffix(primitive, "256") {
+		size = 32
+	}
+	b, err := dec.ReadBytes(size)
+	if err != nil {
+		return nil, err
+	}
+	be := slices.Clone(b)
+	slices.Reverse(be)
+	v := new(big.Int).SetBytes(be)
+	if primitive[0] == 'i' && be[0]&0x80 != 0 {
+		v.Sub(v, new(big.Int).Lsh(big.NewInt(1), uint(size*8)))
+	}
+	return v, nil
+}
+
+// hasGenerics reports whether the generics of a type definition or of a defined type are not empty.
+func hasGenerics(generics json.RawMessage) bool {
+	return len(generics) > 0 && string(generics) != "[]" && string(generics) != "null"
+}
+
+func flattenAnchorAccounts(prefix string, accounts []anchorAccountJSON) []string {
+	var names []string
+	for _, acc := range accounts {
+		name := prefix + acc.Name
+		if len(acc.Accounts) > 0 {
+			names = append(names, flattenAnchorAccounts(name+".", acc.Accounts)...)
+			continue
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+// toSnakeCase converts the camelCase instruction names of the legacy IDLs, e.g. initializeV2 -> initialize_v2.
+func toSnakeCase(name string) string {
+	runes := []rune(name)
+	var sb strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				sb.WriteByte('_')
+			}
+		}
+		sb.WriteRune(unicode.ToLower(r))
+	}
+	return sb.String()
+}
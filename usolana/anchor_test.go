@@ -0,0 +1,338 @@
+package usolana
+
+import (
+	"bytes"
+	"compress/zlib"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/stretchr/testify/assert"
+)
+
+// vaultIDL is an Anchor 0.30+ IDL, the program address is formatted in.
+const vaultIDL = `{
+  "address": "%s",
+  "metadata": {"name": "vault", "version": "0.1.0", "spec": "0.1.0"},
+  "instructions": [
+    {
+      "name": "deposit",
+      "discriminator": [242, 35, 198, 137, 82, 225, 242, 182],
+      "accounts": [
+        {"name": "user", "writable": true, "signer": true},
+        {"name": "vault", "writable": true},
+        {"name": "system_program", "address": "11111111111111111111111111111111"}
+      ],
+      "args": [
+        {"name": "amount", "type": "u64"},
+        {"name": "memo", "type": {"option": "string"}},
+        {"name": "params", "type": {"defined": {"name": "Params"}}}
+      ]
+    }
+  ],
+  "types": [
+    {
+      "name": "Params",
+      "type": {
+        "kind": "struct",
+        "fields": [
+          {"name": "owner", "type": "pubkey"},
+          {"name": "side", "type": {"defined": {"name": "Side"}}},
+          {"name": "weights", "type": {"vec": "u16"}},
+          {"name": "delta", "type": "i128"},
+          {"name": "pair", "type": {"array": ["u8", 2]}}
+        ]
+      }
+    },
+    {
+      "name": "Side",
+      "type": {
+        "kind": "enum",
+        "variants": [
+          {"name": "Bid"},
+          {"name": "Ask", "fields": [{"name": "price", "type": "u64"}]}
+        ]
+      }
+    }
+  ]
+}`
+
+// counterIDL is a legacy Anchor IDL without discriminators.
+const counterIDL = `{
+  "version": "0.1.0",
+  "name": "counter",
+  "instructions": [
+    {
+      "name": "incrementBy",
+      "accounts": [
+        {"name": "counter", "isMut": true, "isSigner": false},
+        {"name": "auth", "accounts": [{"name": "authority", "isMut": false, "isSigner": true}]}
+      ],
+      "args": [
+        {"name": "by", "type": "u32"},
+        {"name": "who", "type": "publicKey"},
+        {"name": "cfg", "type": {"defined": "Cfg"}}
+      ]
+    }
+  ],
+  "types": [
+    {"name": "Cfg", "type": {"kind": "struct", "fields": [{"name": "flag", "type": "bool"}]}}
+  ],
+  "metadata": {"address": "%s"}
+}`
+
+// genericIDL declares generic types, only the instructions using them fail to decode.
+const genericIDL = `{
+  "address": "11111111111111111111111111111111",
+  "metadata": {"name": "generic", "version": "0.1.0", "spec": "0.1.0"},
+  "instructions": [
+    {"name": "plain", "discriminator": [1, 0, 0, 0, 0, 0, 0, 0], "accounts": [], "args": [{"name": "value", "type": "u8"}]},
+    {
+      "name": "wrapped",
+      "discriminator": [2, 0, 0, 0, 0, 0, 0, 0],
+      "accounts": [],
+      "args": [{"name": "value", "type": {"defined": {"name": "Wrapper", "generics": [{"kind": "type", "type": "u64"}]}}}]
+    },
+    {"name": "fixed", "discriminator": [3, 0, 0, 0, 0, 0, 0, 0], "accounts": [], "args": [{"name": "value", "type": {"defined": {"name": "Fixed"}}}]}
+  ],
+  "types": [
+    {
+      "name": "Wrapper",
+      "generics": [{"kind": "type", "name": "T"}],
+      "type": {"kind": "struct", "fields": [{"name": "inner", "type": {"generic": "T"}}]}
+    },
+    {
+      "name": "Fixed",
+      "generics": [{"kind": "const", "name": "N", "type": "usize"}],
+      "type": {"kind": "struct", "fields": [{"name": "data", "type": {"array": ["u8", {"generic": "N"}]}}]}
+    }
+  ]
+}`
+
+// newAnchorTestTx returns a signed transaction with one instruction of the program.
+func newAnchorTestTx(t *testing.T, payer *solana.Wallet, program solana.PublicKey, accounts solana.AccountMetaSlice, data []byte) rpc.TransactionWithMeta {
+	tx, err := solana.NewTransaction(
+		[]solana.Instruction{solana.NewInstruction(program, accounts, data)},
+		solana.Hash{1},
+		solana.TransactionPayer(payer.PublicKey()),
+	)
+	assert.NoError(t, err)
+	_, err = tx.Sign(func(solana.PublicKey) *solana.PrivateKey { return &payer.PrivateKey })
+	assert.NoError(t, err)
+	txBytes, err := tx.MarshalBinary()
+	assert.NoError(t, err)
+	cu := uint64(150)
+	return rpc.TransactionWithMeta{
+		Transaction: rpc.DataBytesOrJSONFromBytes(txBytes),
+		Meta:        &rpc.TransactionMeta{ComputeUnitsConsumed: &cu},
+		Version:     rpc.LegacyTransactionVersion,
+	}
+}
+
+func TestAnchorIDL(t *testing.T) {
+	payer := solana.NewWallet()
+	program, vault, owner := solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey()
+
+	idl, err := ParseAnchorIDL(fmt.Appendf(nil, vaultIDL, program))
+	assert.NoError(t, err)
+	assert.Equal(t, program.String(), idl.ProgramID)
+	assert.Equal(t, "vault", idl.Name)
+
+	// deposit(amount: 1000, memo: Some("hi"), params: {owner, side: Ask{price: 7}, weights: [1, 2], delta: -5, pair: [3, 4]})
+	data := []byte{242, 35, 198, 137, 82, 225, 242, 182}
+	data = binary.LittleEndian.AppendUint64(data, 1000)
+	data = append(data, 1)
+	data = binary.LittleEndian.AppendUint32(data, 2)
+	data = append(data, "hi"...)
+	data = append(data, owner.Bytes()...)
+	data = append(data, 1)
+	data = binary.LittleEndian.AppendUint64(data, 7)
+	data = binary.LittleEndian.AppendUint32(data, 2)
+	data = binary.LittleEndian.AppendUint16(data, 1)
+	data = binary.LittleEndian.AppendUint16(data, 2)
+	data = append(data, bytes.Repeat([]byte{0xff}, 16)...)
+	data[len(data)-16] = 0xfb
+	data = append(data, 3, 4)
+
+	twm := newAnchorTestTx(t, payer, program, solana.AccountMetaSlice{
+		solana.Meta(payer.PublicKey()).WRITE().SIGNER(),
+		solana.Meta(vault).WRITE(),
+		solana.Meta(solana.SystemProgramID),
+	}, data)
+	tp := NewTxParser(rpc.LocalNet_RPC)
+	assert.NoError(t, tp.RegisterAnchorIDL(idl))
+	ptx, err := tp.parseConfirmedTx(twm)
+	assert.NoError(t, err)
+	assert.Equal(t, ParsedInstruction{
+		ProgramID: program.String(),
+		TypeID:    0,
+		Name:      "deposit",
+		Accounts: []ParsedInstructionAccount{
+			{Address: payer.PublicKey().String(), IsWritable: true, IsSigner: true, Name: "user"},
+			{Address: vault.String(), IsWritable: true, Name: "vault"},
+			{Address: solana.SystemProgramID.String(), Name: "system_program"},
+		},
+		Data: map[string]any{
+			"amount": uint64(1000),
+			"memo":   "hi",
+			"params": map[string]any{
+				"owner":   owner.String(),
+				"side":    map[string]any{"Ask": map[string]any{"price": uint64(7)}},
+				"weights": []any{uint16(1), uint16(2)},
+				"delta":   big.NewInt(-5),
+				"pair":    []any{uint8(3), uint8(4)},
+			},
+		},
+	}, ptx.Instructions[0])
+
+	t.Run("unknown instruction", func(t *testing.T) {
+		twm := newAnchorTestTx(t, payer, program, nil, []byte{1, 2, 3, 4, 5, 6, 7, 8})
+		ptx, err := tp.parseConfirmedTx(twm)
+		assert.NoError(t, err)
+		assert.Equal(t, "Unknown", ptx.Instructions[0].Name)
+		assert.Equal(t, program.String(), ptx.Instructions[0].ProgramID)
+	})
+
+	t.Run("truncated data", func(t *testing.T) {
+		_, err := idl.ParseInstruction(&solana.Transaction{}, solana.CompiledInstruction{Data: data[:20]})
+		assert.Error(t, err)
+
+		// the undecodable instruction is kept as "Unknown" with the named accounts
+		twm := newAnchorTestTx(t, payer, program, solana.AccountMetaSlice{
+			solana.Meta(payer.PublicKey()).WRITE().SIGNER(),
+			solana.Meta(vault).WRITE(),
+		}, data[:20])
+		ptx, err := tp.parseConfirmedTx(twm)
+		assert.NoError(t, err)
+		assert.Equal(t, []ParsedInstruction{{
+			ProgramID: program.String(),
+			Name:      "Unknown",
+			Accounts: []ParsedInstructionAccount{
+				{Address: payer.PublicKey().String(), IsWritable: true, IsSigner: true, Name: "user"},
+				{Address: vault.String(), IsWritable: true, Name: "vault"},
+			},
+			Data: solana.Base58(data[:20]).String(),
+		}}, ptx.Instructions)
+	})
+
+	t.Run("legacy", func(t *testing.T) {
+		counter := solana.NewWallet().PublicKey()
+		path := filepath.Join(t.TempDir(), "counter.json")
+		assert.NoError(t, os.WriteFile(path, fmt.Appendf(nil, counterIDL, program), 0o600))
+		idl, err := LoadAnchorIDL(path)
+		assert.NoError(t, err)
+		assert.Equal(t, program.String(), idl.ProgramID)
+		assert.Equal(t, "counter", idl.Name)
+
+		sum := sha256.Sum256([]byte("global:increment_by"))
+		data := binary.LittleEndian.AppendUint32(sum[:8], 3)
+		data = append(data, owner.Bytes()...)
+		data = append(data, 1)
+		twm := newAnchorTestTx(t, payer, program, solana.AccountMetaSlice{
+			solana.Meta(counter).WRITE(),
+			solana.Meta(payer.PublicKey()).SIGNER(),
+		}, data)
+
+		tp := NewTxParser(rpc.LocalNet_RPC)
+		assert.NoError(t, tp.RegisterAnchorIDL(idl))
+		ptx, err := tp.parseConfirmedTx(twm)
+		assert.NoError(t, err)
+		ins := ptx.Instructions[0]
+		assert.Equal(t, "incrementBy", ins.Name)
+		assert.Equal(t, "counter", ins.Accounts[0].Name)
+		assert.Equal(t, "auth.authority", ins.Accounts[1].Name)
+		assert.Equal(t, map[string]any{"by": uint32(3), "who": owner.String(), "cfg": map[string]any{"flag": true}}, ins.Data)
+	})
+
+	t.Run("fetch", func(t *testing.T) {
+		var compressed bytes.Buffer
+		zw := zlib.NewWriter(&compressed)
+		_, err := zw.Write(fmt.Appendf(nil, vaultIDL, program))
+		assert.NoError(t, err)
+		assert.NoError(t, zw.Close())
+		account := make([]byte, 40, 44+compressed.Len())
+		account = binary.LittleEndian.AppendUint32(account, uint32(compressed.Len()))
+		account = append(account, compressed.Bytes()...)
+
+		idlAddress, err := AnchorIDLAddress(program)
+		assert.NoError(t, err)
+		srv := httptest.NewServer(&mockSolanaRPC{accounts: map[string][]byte{idlAddress.String(): account}})
+		defer srv.Close()
+		tp := NewTxParser(srv.URL)
+
+		idl, err := tp.FetchAnchorIDL(t.Context(), program.String())
+		assert.NoError(t, err)
+		assert.Equal(t, "vault", idl.Name)
+		assert.NoError(t, tp.RegisterAnchorIDL(idl))
+		ptx, err := tp.parseConfirmedTx(twm)
+		assert.NoError(t, err)
+		assert.Equal(t, "deposit", ptx.Instructions[0].Name)
+
+		_, err = tp.FetchAnchorIDL(t.Context(), solana.NewWallet().PublicKey().String())
+		assert.ErrorIs(t, err, rpc.ErrNotFound)
+	})
+
+	assert.Error(t, tp.RegisterAnchorIDL(&AnchorIDL{}))
+}
+
+func TestAnchorIDLGenerics(t *testing.T) {
+	idl, err := ParseAnchorIDL([]byte(genericIDL))
+	assert.NoError(t, err)
+
+	ins, err := idl.ParseInstruction(&solana.Transaction{}, solana.CompiledInstruction{Data: []byte{1, 0, 0, 0, 0, 0, 0, 0, 7}})
+	assert.NoError(t, err)
+	assert.Equal(t, "plain", ins.Name)
+	assert.Equal(t, map[string]any{"value": uint8(7)}, ins.Data)
+
+	for _, discriminator := range []byte{2, 3} {
+		data := append([]byte{discriminator, 0, 0, 0, 0, 0, 0, 0}, make([]byte, 8)...)
+		_, err := idl.ParseInstruction(&solana.Transaction{}, solana.CompiledInstruction{Data: data})
+		assert.ErrorContains(t, err, "unsupported generic")
+	}
+}
+
+func TestAnchorIDLLimits(t *testing.T) {
+	// recursive types and a huge array length, e.g. in an IDL fetched from an untrusted program
+	idl, err := ParseAnchorIDL([]byte(`{
+	  "address": "11111111111111111111111111111111",
+	  "metadata": {"name": "hostile"},
+	  "instructions": [
+	    {"name": "alias", "discriminator": [1, 0, 0, 0, 0, 0, 0, 0], "accounts": [], "args": [{"name": "a", "type": {"defined": {"name": "A"}}}]},
+	    {"name": "node", "discriminator": [2, 0, 0, 0, 0, 0, 0, 0], "accounts": [], "args": [{"name": "n", "type": {"defined": {"name": "Node"}}}]},
+	    {"name": "huge", "discriminator": [3, 0, 0, 0, 0, 0, 0, 0], "accounts": [], "args": [{"name": "h", "type": {"array": ["u8", 2000000000]}}]}
+	  ],
+	  "types": [
+	    {"name": "A", "type": {"kind": "type", "alias": {"defined": {"name": "A"}}}},
+	    {"name": "Node", "type": {"kind": "struct", "fields": [{"name": "next", "type": {"defined": {"name": "Node"}}}]}}
+	  ]
+	}`))
+	assert.NoError(t, err)
+	for discriminator, contains := range map[byte]string{1: "type nesting", 2: "type nesting", 3: "exceeds the remaining"} {
+		data := append([]byte{discriminator, 0, 0, 0, 0, 0, 0, 0}, make([]byte, 16)...)
+		_, err := idl.ParseInstruction(&solana.Transaction{}, solana.CompiledInstruction{Data: data})
+		assert.ErrorContains(t, err, contains)
+	}
+
+	_, err = ParseAnchorIDL([]byte(`{"instructions": [{"name": "neg", "args": [{"name": "n", "type": {"array": ["u8", -1]}}]}]}`))
+	assert.Error(t, err)
+}
+
+func TestToSnakeCase(t *testing.T) {
+	for name, want := range map[string]string{
+		"initialize":        "initialize",
+		"incrementBy":       "increment_by",
+		"initializeV2":      "initialize_v2",
+		"createATAAccount":  "create_ata_account",
+		"already_snake":     "already_snake",
+		"setAuthorityCheck": "set_authority_check",
+	} {
+		assert.Equal(t, want, toSnakeCase(name), name)
+	}
+}
@@ -0,0 +1,125 @@
+package usolana
+
+import (
+	"fmt"
+	"math/big"
+	"slices"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// BalanceChange is the SOL balance change of an account in a transaction, the fee is included in the fee payer's change.
+type BalanceChange struct {
+	Account string // account address // base58
+	Pre     uint64 // balance before the transaction // lamports
+	Post    uint64 // balance after the transaction // lamports
+	Delta   int64  // = post - pre // lamports
+}
+
+// TokenBalanceChange is the SPL token balance change of an owner for a mint in a transaction,
+// the balances of the owner's token accounts of the mint are summed.
+type TokenBalanceChange struct {
+	Owner     string   // token account owner // base58 // the token account address when the node does not return the owner
+	Mint      string   // token mint address // base58
+	ProgramID string   // token program // Token or Token-2022
+	Decimals  uint8    // mint decimals
+	Pre       *big.Int // balance before the transaction // raw amount
+	Post      *big.Int // balance after the transaction // raw amount
+	Delta     *big.Int // = post - pre // raw amount
+}
+
+// parseBalanceChanges returns the SOL balance changes of the accounts whose balance changed, in account order,
+// accountKeys include the addresses loaded from lookup tables.
+func parseBalanceChanges(accountKeys solana.PublicKeySlice, meta *rpc.TransactionMeta) ([]BalanceChange, error) {
+	if len(meta.PreBalances) != len(meta.PostBalances) || len(meta.PreBalances) > len(accountKeys) {
+		return nil, fmt.Errorf("%d pre balances and %d post balances for %d accounts",
+			len(meta.PreBalances), len(meta.PostBalances), len(accountKeys))
+	}
+	var changes []BalanceChange
+	slices.All(meta.PreBalances)(func(idx int, pre uint64) bool {
+		post := meta.PostBalances[idx]
+		if pre == post {
+			return true
+		}
+		changes = append(changes, BalanceChange{
+			Account: accountKeys[idx].String(),
+			Pre:     pre,
+			Post:    post,
+			Delta:   int64(post) - int64(pre),
+		})
+		return true
+	})
+	return changes, nil
+}
+
+// parseTokenBalanceChanges returns the token balance changes of the (owner, mint) pairs whose balance changed,
+// in the order of their first token account. A token account created by the transaction has no pre balance,
+// a closed token account has no post balance.
+func parseTokenBalanceChanges(accountKeys solana.PublicKeySlice, meta *rpc.TransactionMeta) ([]TokenBalanceChange, error) {
+	type ownerMint struct {
+		owner, mint string
+	}
+	var keys []ownerMint
+	changes := make(map[ownerMint]*TokenBalanceChange)
+
+	add := func(tb rpc.TokenBalance, post bool) error {
+		if int(tb.AccountIndex) >= len(accountKeys) {
+			return fmt.Errorf("token balance account index %d out of %d accounts", tb.AccountIndex, len(accountKeys))
+		}
+		if tb.UiTokenAmount == nil {
+			return fmt.Errorf("token balance of account %d has no amount", tb.AccountIndex)
+		}
+		amount, ok := new(big.Int).SetString(tb.UiTokenAmount.Amount, 10)
+		if !ok {
+			return fmt.Errorf("invalid token amount %q of account %d", tb.UiTokenAmount.Amount, tb.AccountIndex)
+		}
+
+		key := ownerMint{owner: accountKeys[tb.AccountIndex].String(), mint: tb.Mint.String()}
+		if tb.Owner != nil {
+			key.owner = tb.Owner.String()
+		}
+		change, ok := changes[key]
+		if !ok {
+			change = &TokenBalanceChange{
+				Owner:    key.owner,
+				Mint:     key.mint,
+				Decimals: tb.UiTokenAmount.Decimals,
+				Pre:      new(big.Int),
+				Post:     new(big.Int),
+			}
+			if tb.ProgramId != nil {
+				change.ProgramID = tb.ProgramId.String()
+			}
+			changes[key] = change
+			keys = append(keys, key)
+		}
+		if post {
+			change.Post.Add(change.Post, amount)
+		} else {
+			change.Pre.Add(change.Pre, amount)
+		}
+		return nil
+	}
+	for _, tb := range meta.PreTokenBalances {
+		if err := add(tb, false); err != nil {
+			return nil, err
+		}
+	}
+	for _, tb := range meta.PostTokenBalances {
+		if err := add(tb, true); err != nil {
+			return nil, err
+		}
+	}
+
+	var res []TokenBalanceChange
+	slices.Values(keys)(func(key ownerMint) bool {
+		change := changes[key]
+		change.Delta = new(big.Int).Sub(change.Post, change.Pre)
+		if change.Delta.Sign() != 0 {
+			res = append(res, *change)
+		}
+		return true
+	})
+	return res, nil
+}
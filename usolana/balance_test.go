@@ -0,0 +1,82 @@
+package usolana
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseBalanceChanges(t *testing.T) {
+	payer, recipient := solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey()
+	loaded := solana.NewWallet().PublicKey()
+	accountKeys := solana.PublicKeySlice{payer, recipient, solana.SystemProgramID, loaded}
+	meta := &rpc.TransactionMeta{
+		PreBalances:  []uint64{10000, 0, 1, 7},
+		PostBalances: []uint64{4000, 5000, 1, 8},
+	}
+
+	changes, err := parseBalanceChanges(accountKeys, meta)
+	assert.NoError(t, err)
+	assert.Equal(t, []BalanceChange{
+		{Account: payer.String(), Pre: 10000, Post: 4000, Delta: -6000},
+		{Account: recipient.String(), Pre: 0, Post: 5000, Delta: 5000},
+		{Account: loaded.String(), Pre: 7, Post: 8, Delta: 1},
+	}, changes)
+
+	_, err = parseBalanceChanges(accountKeys[:3], meta)
+	assert.Error(t, err)
+}
+
+func TestParseTokenBalanceChanges(t *testing.T) {
+	owner, recipient := solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey()
+	ownerATA, ownerATA2, recipientATA := solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey()
+	mint := solana.NewWallet().PublicKey()
+	accountKeys := solana.PublicKeySlice{owner, ownerATA, ownerATA2, recipientATA, solana.TokenProgramID}
+	balance := func(accountIndex uint16, owner solana.PublicKey, amount string) rpc.TokenBalance {
+		return rpc.TokenBalance{
+			AccountIndex:  accountIndex,
+			Owner:         &owner,
+			ProgramId:     &solana.TokenProgramID,
+			Mint:          mint,
+			UiTokenAmount: &rpc.UiTokenAmount{Amount: amount, Decimals: 6},
+		}
+	}
+
+	meta := &rpc.TransactionMeta{
+		// the second token account of the owner is closed, the token account of the recipient is created
+		PreTokenBalances:  []rpc.TokenBalance{balance(1, owner, "1000000"), balance(2, owner, "500")},
+		PostTokenBalances: []rpc.TokenBalance{balance(1, owner, "400000"), balance(3, recipient, "600500")},
+	}
+	changes, err := parseTokenBalanceChanges(accountKeys, meta)
+	assert.NoError(t, err)
+	assert.Equal(t, []TokenBalanceChange{
+		{
+			Owner: owner.String(), Mint: mint.String(), ProgramID: solana.TokenProgramID.String(), Decimals: 6,
+			Pre: big.NewInt(1000500), Post: big.NewInt(400000), Delta: big.NewInt(-600500),
+		},
+		{
+			Owner: recipient.String(), Mint: mint.String(), ProgramID: solana.TokenProgramID.String(), Decimals: 6,
+			Pre: big.NewInt(0), Post: big.NewInt(600500), Delta: big.NewInt(600500),
+		},
+	}, changes)
+
+	t.Run("unchanged", func(t *testing.T) {
+		meta := &rpc.TransactionMeta{
+			PreTokenBalances:  []rpc.TokenBalance{balance(1, owner, "1")},
+			PostTokenBalances: []rpc.TokenBalance{balance(1, owner, "1")},
+		}
+		changes, err := parseTokenBalanceChanges(accountKeys, meta)
+		assert.NoError(t, err)
+		assert.Empty(t, changes)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := parseTokenBalanceChanges(accountKeys, &rpc.TransactionMeta{PostTokenBalances: []rpc.TokenBalance{balance(9, owner, "1")}})
+		assert.Error(t, err)
+		_, err = parseTokenBalanceChanges(accountKeys, &rpc.TransactionMeta{PostTokenBalances: []rpc.TokenBalance{balance(1, owner, "1.5")}})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,159 @@
+package usolana
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/15ho/wallet-utils-go/internal/zlog"
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"go.uber.org/zap"
+)
+
+// ErrTxExpired is returned when the recent blockhash of a transaction expired before the transaction was processed,
+// it will never be processed and can be signed again with a new blockhash.
+var ErrTxExpired = errors.New("transaction expired")
+
+// ConfirmationOptions configures a ConfirmationTracker.
+type ConfirmationOptions struct {
+	// Commitment is the commitment level to wait for: processed, confirmed or finalized.
+	Commitment rpc.CommitmentType
+	// PollInterval is the time between two polls of the node.
+	PollInterval time.Duration
+}
+
+// DefaultConfirmationOptions are used when a ConfirmationTracker is created without options.
+var DefaultConfirmationOptions = ConfirmationOptions{
+	Commitment:   rpc.CommitmentConfirmed,
+	PollInterval: time.Second,
+}
+
+// ConfirmationTracker waits for sent transactions to reach a commitment level.
+type ConfirmationTracker struct {
+	tp   *TxParser
+	opts ConfirmationOptions
+}
+
+// ConfirmationTracker returns a confirmation tracker parsing the transactions with the instruction parsers of the TxParser,
+// the zero fields of the options are set from DefaultConfirmationOptions.
+func (tp *TxParser) ConfirmationTracker(optsOption ...ConfirmationOptions) *ConfirmationTracker {
+	opts := DefaultConfirmationOptions
+	if len(optsOption) > 0 {
+		opts = optsOption[0]
+	}
+	if opts.Commitment == "" {
+		opts.Commitment = DefaultConfirmationOptions.Commitment
+	}
+	if opts.PollInterval == 0 {
+		opts.PollInterval = DefaultConfirmationOptions.PollInterval
+	}
+	return &ConfirmationTracker{tp: tp, opts: opts}
+}
+
+// ConfirmationTracker returns a confirmation tracker of the transactions sent by the WalletClient.
+func (wc *WalletClient) ConfirmationTracker(optsOption ...ConfirmationOptions) *ConfirmationTracker {
+	tp := &TxParser{
+		cli:              wc.cli,
+		insParserFactory: newInstructionsParserFactory(),
+	}
+	return tp.ConfirmationTracker(optsOption...)
+}
+
+// commitmentRank orders the commitment levels, a transaction at a level has reached the lower levels.
+var commitmentRank = map[string]int{
+	string(rpc.CommitmentProcessed): 1,
+	string(rpc.CommitmentConfirmed): 2,
+	string(rpc.CommitmentFinalized): 3,
+}
+
+// Wait polls the node until the transaction reaches the Commitment level and returns it parsed like TxParser.ParseBlock,
+// a failed transaction is returned with the status "fail".
+// ErrTxExpired is returned when the block height passes the last valid block height of the transaction's blockhash
+// and the transaction was not processed. Without lastValidBlockHeightOption (returned by getLatestBlockhash with the blockhash),
+// the transaction expires BlockhashValidBlocks blocks after the call.
+func (ct *ConfirmationTracker) Wait(ctx context.Context, signature string, lastValidBlockHeightOption ...uint64) (ptx *ParsedTx, err error) {
+	sig, err := solana.SignatureFromBase58(signature)
+	if err != nil {
+		err = fmt.Errorf("invalid signature: %w", err)
+		return
+	}
+	var lastValidBlockHeight uint64
+	if len(lastValidBlockHeightOption) > 0 {
+		lastValidBlockHeight = lastValidBlockHeightOption[0]
+	} else {
+		var blockHeight uint64
+		if blockHeight, err = ct.tp.cli.GetBlockHeight(ctx, rpc.CommitmentConfirmed); err != nil {
+			err = fmt.Errorf("get block height: %w", err)
+			return
+		}
+		lastValidBlockHeight = blockHeight + BlockhashValidBlocks
+	}
+
+	ticker := time.NewTicker(ct.opts.PollInterval)
+	defer ticker.Stop()
+	for {
+		var confirmed bool
+		if ptx, confirmed, err = ct.poll(ctx, sig, lastValidBlockHeight); err != nil || confirmed {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			err = ctx.Err()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (ct *ConfirmationTracker) poll(ctx context.Context, sig solana.Signature, lastValidBlockHeight uint64) (ptx *ParsedTx, confirmed bool, err error) {
+	cli := ct.tp.cli
+	// read before the status: the transaction was not processed when the block height passed
+	blockHeight, err := cli.GetBlockHeight(ctx, rpc.CommitmentConfirmed)
+	if err != nil {
+		err = fmt.Errorf("get block height: %w", err)
+		return
+	}
+	statuses, err := cli.GetSignatureStatuses(ctx, false, sig)
+	if err != nil {
+		err = fmt.Errorf("get signature statuses: %w", err)
+		return
+	}
+	var status *rpc.SignatureStatusesResult
+	if len(statuses.Value) > 0 {
+		status = statuses.Value[0]
+	}
+	if status == nil {
+		if blockHeight > lastValidBlockHeight {
+			err = fmt.Errorf("%w: %s, block height %d > last valid block height %d", ErrTxExpired, sig, blockHeight, lastValidBlockHeight)
+		}
+		return
+	}
+	if commitmentRank[string(status.ConfirmationStatus)] < commitmentRank[string(ct.opts.Commitment)] {
+		zlog.Debug("waiting for commitment",
+			zap.String("signature", sig.String()),
+			zap.String("status", string(status.ConfirmationStatus)))
+		return
+	}
+
+	// getTransaction does not support processed
+	commitment := rpc.CommitmentConfirmed
+	if ct.opts.Commitment == rpc.CommitmentFinalized {
+		commitment = rpc.CommitmentFinalized
+	}
+	twm, err := ct.tp.getTransaction(ctx, sig, commitment)
+	if errors.Is(err, rpc.ErrNotFound) {
+		// processed, not confirmed yet
+		err = nil
+		return
+	}
+	if err != nil {
+		return
+	}
+	if ptx, err = ct.tp.parseConfirmedTx(twm); err != nil {
+		return
+	}
+	confirmed = true
+	return
+}
@@ -0,0 +1,197 @@
+package usolana
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockSolanaRPC serves the JSON-RPC methods used by the TxParser and the ConfirmationTracker for one transaction,
+// the transaction is in the block of slot 100 and slot 101 is skipped.
+type mockSolanaRPC struct {
+	mu          sync.Mutex
+	blockHeight uint64
+	status      rpc.ConfirmationStatusType // empty: unknown signature
+	txErr       any
+	tx          *solana.Transaction
+	brokenTx    bool              // the block of slot 100 starts with the transaction without its meta
+	accounts    map[string][]byte // getAccountInfo data by address
+}
+
+func (m *mockSolanaRPC) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ID     json.RawMessage `json:"id"`
+		Method string          `json:"method"`
+		Params []any           `json:"params"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var result any
+	switch req.Method {
+	case "getBlockHeight":
+		result = m.blockHeight
+	case "getSignatureStatuses":
+		var status any
+		if m.status != "" {
+			status = map[string]any{"slot": 100, "confirmations": nil, "err": m.txErr, "confirmationStatus": m.status}
+		}
+		result = map[string]any{"context": map[string]any{"slot": 100}, "value": []any{status}}
+	case "getTransaction":
+		if m.status == "" || m.status == rpc.ConfirmationStatusProcessed {
+			break
+		}
+		result = map[string]any{
+			"slot":        100,
+			"blockTime":   1700000000,
+			"transaction": m.txBase64(),
+			"meta":        m.txMeta(),
+			"version":     "legacy",
+		}
+	case "getAccountInfo":
+		var value any
+		if data, ok := m.accounts[req.Params[0].(string)]; ok {
+			value = map[string]any{
+				"data":       []string{base64.StdEncoding.EncodeToString(data), "base64"},
+				"executable": false,
+				"lamports":   1,
+				"owner":      solana.SystemProgramID.String(),
+				"rentEpoch":  0,
+				"space":      len(data),
+			}
+		}
+		result = map[string]any{"context": map[string]any{"slot": 100}, "value": value}
+	case "getBlock":
+		var txs []any
+		switch slot := req.Params[0].(float64); slot {
+		case 100:
+			if m.brokenTx {
+				txs = append(txs, map[string]any{"transaction": m.txBase64(), "meta": nil, "version": "legacy"})
+			}
+			txs = append(txs, map[string]any{"transaction": m.txBase64(), "meta": m.txMeta(), "version": "legacy"})
+		case 101:
+			resp := map[string]any{"code": -32007, "message": "Slot 101 was skipped, or missing due to ledger jump to recent snapshot"}
+			_ = json.NewEncoder(w).Encode(map[string]any{"jsonrpc": "2.0", "id": req.ID, "error": resp})
+			return
+		}
+		result = map[string]any{
+			"blockhash":         solana.Hash{2}.String(),
+			"previousBlockhash": solana.Hash{1}.String(),
+			"parentSlot":        99,
+			"blockTime":         1700000000,
+			"transactions":      txs,
+		}
+	}
+	_ = json.NewEncoder(w).Encode(map[string]any{"jsonrpc": "2.0", "id": req.ID, "result": result})
+}
+
+func (m *mockSolanaRPC) txBase64() []string {
+	txBytes, _ := m.tx.MarshalBinary()
+	return []string{base64.StdEncoding.EncodeToString(txBytes), "base64"}
+}
+
+func (m *mockSolanaRPC) txMeta() map[string]any {
+	return map[string]any{
+		"err":                  m.txErr,
+		"fee":                  5000,
+		"preBalances":          []uint64{10, 0, 1},
+		"postBalances":         []uint64{5, 5, 1},
+		"innerInstructions":    []any{},
+		"logMessages":          []string{},
+		"computeUnitsConsumed": 150,
+	}
+}
+
+func (m *mockSolanaRPC) set(f func(m *mockSolanaRPC)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	f(m)
+}
+
+// newSignedTransferTx returns a signed transfer of 5 lamports from payer.
+func newSignedTransferTx(t *testing.T, payer *solana.Wallet) *solana.Transaction {
+	tx, err := solana.NewTransaction(
+		[]solana.Instruction{system.NewTransferInstruction(5, payer.PublicKey(), solana.NewWallet().PublicKey()).Build()},
+		solana.Hash{1},
+		solana.TransactionPayer(payer.PublicKey()),
+	)
+	assert.NoError(t, err)
+	_, err = tx.Sign(func(solana.PublicKey) *solana.PrivateKey { return &payer.PrivateKey })
+	assert.NoError(t, err)
+	return tx
+}
+
+func TestConfirmationTracker(t *testing.T) {
+	ctx := context.Background()
+	payer := solana.NewWallet()
+	tx := newSignedTransferTx(t, payer)
+	signature := tx.Signatures[0].String()
+
+	m := &mockSolanaRPC{blockHeight: 1000, tx: tx}
+	srv := httptest.NewServer(m)
+	defer srv.Close()
+	ct := NewTxParser(srv.URL).ConfirmationTracker(ConfirmationOptions{
+		Commitment:   rpc.CommitmentFinalized,
+		PollInterval: 10 * time.Millisecond,
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		_, err := ct.Wait(ctx, signature, 999)
+		assert.ErrorIs(t, err, ErrTxExpired)
+	})
+
+	t.Run("finalized", func(t *testing.T) {
+		m.set(func(m *mockSolanaRPC) { m.status = rpc.ConfirmationStatusConfirmed })
+		go func() {
+			time.Sleep(30 * time.Millisecond)
+			m.set(func(m *mockSolanaRPC) { m.status = rpc.ConfirmationStatusFinalized })
+		}()
+		ptx, err := ct.Wait(ctx, signature)
+		assert.NoError(t, err)
+		assert.Equal(t, signature, ptx.TxHash)
+		assert.Equal(t, uint64(100), ptx.Block)
+		assert.Equal(t, "success", ptx.Status)
+		assert.Equal(t, uint64(5000), ptx.Fee)
+		assert.Equal(t, []string{payer.PublicKey().String()}, ptx.Signer)
+		assert.Equal(t, "Transfer", ptx.Instructions[0].Name)
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		m.set(func(m *mockSolanaRPC) { m.txErr = map[string]any{"InstructionError": []any{0, "Custom"}} })
+		ptx, err := ct.Wait(ctx, signature)
+		assert.NoError(t, err)
+		assert.Equal(t, "fail", ptx.Status)
+	})
+}
+
+func TestConfirmationTrackerPartialOptions(t *testing.T) {
+	tp := NewTxParser(rpc.LocalNet_RPC)
+	ct := tp.ConfirmationTracker(ConfirmationOptions{Commitment: rpc.CommitmentFinalized})
+	assert.Equal(t, ConfirmationOptions{Commitment: rpc.CommitmentFinalized, PollInterval: DefaultConfirmationOptions.PollInterval}, ct.opts)
+
+	// a processed transaction is not confirmed without a commitment
+	tx := newSignedTransferTx(t, solana.NewWallet())
+	m := &mockSolanaRPC{blockHeight: 1000, tx: tx, status: rpc.ConfirmationStatusProcessed}
+	srv := httptest.NewServer(m)
+	defer srv.Close()
+	ct = NewTxParser(srv.URL).ConfirmationTracker(ConfirmationOptions{PollInterval: 10 * time.Millisecond})
+	assert.Equal(t, DefaultConfirmationOptions.Commitment, ct.opts.Commitment)
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err := ct.Wait(ctx, tx.Signatures[0].String())
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
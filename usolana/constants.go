@@ -6,3 +6,14 @@ const (
 	MicroLamportsPerSOL     uint64 = MicroLamportsPerLamport * LamportsPerSOL // 1 SOL = 1,000,000,000,000 MicroLamports
 	SOLDecimals             uint8  = 9
 )
+
+// BIP-0044 derivation paths, the coin types are registered in https://github.com/satoshilabs/slips/blob/master/slip-0044.md
+const (
+	// DerivationPathFormat is the SLIP-0010 path used by Phantom and Solflare, the placeholder is the account index.
+	// ed25519 only supports hardened derivation, so every level is hardened.
+	DerivationPathFormat = "m/44'/501'/%d'/0'"
+)
+
+// BlockhashValidBlocks is the number of blocks a recent blockhash is valid for, the transactions using it expire after.
+// https://solana.com/docs/advanced/confirmation#how-does-transaction-expiration-work
+const BlockhashValidBlocks uint64 = 150
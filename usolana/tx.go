@@ -4,9 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"iter"
 	"math/big"
 	"slices"
 
+	"github.com/15ho/wallet-utils-go/internal/blockrange"
 	"github.com/15ho/wallet-utils-go/internal/zlog"
 	bin "github.com/gagliardetto/binary"
 	"github.com/gagliardetto/solana-go"
@@ -19,6 +21,7 @@ import (
 	"github.com/gagliardetto/solana-go/programs/tokenregistry"
 	"github.com/gagliardetto/solana-go/programs/vote"
 	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/gagliardetto/solana-go/rpc/jsonrpc"
 	"go.uber.org/zap"
 )
 
@@ -34,6 +37,7 @@ type ParsedInstructionAccount struct {
 	Address    string // base58
 	IsWritable bool
 	IsSigner   bool
+	Name       string // account name // set by the instruction parsers knowing it, e.g. Anchor IDL
 }
 
 type ParsedInstruction struct {
@@ -53,15 +57,18 @@ type ParsedTx struct {
 	Status               string   // transaction status // success or fail
 	Signer               []string // signer addresses
 	Instructions         []ParsedInstruction
-	Fee                  uint64 // transaction fee // = base fee + priority fee // lamports
-	PriorityFee          uint64 // transaction priority fee // = (compute unit price * compute unit limit) / microLamportsPerLamport // lamports
-	ComputeUnitsConsumed uint64 // compute units consumed
-	TxVersion            int    // transaction version // -1: legacy
+	Fee                  uint64               // transaction fee // = base fee + priority fee // lamports
+	PriorityFee          uint64               // transaction priority fee // = (compute unit price * compute unit limit) / microLamportsPerLamport // lamports
+	ComputeUnitsConsumed uint64               // compute units consumed
+	TxVersion            int                  // transaction version // -1: legacy
+	BalanceChanges       []BalanceChange      // SOL balance changes
+	TokenBalanceChanges  []TokenBalanceChange // SPL token balance changes per owner and mint
 }
 
 type TxParser struct {
 	cli              *rpc.Client
 	insParserFactory instructionsParserFactory
+	strict           bool
 }
 
 func NewTxParser(endpoint string) *TxParser {
@@ -79,7 +86,7 @@ func NewDevnetTxParser() *TxParser {
 	return NewTxParser(rpc.DevNet_RPC)
 }
 
-func (tp *TxParser) ParseLatestBlock(ctx context.Context) ([]*ParsedTx, error) {
+func (tp *TxParser) ParseLatestBlock(ctx context.Context) (*ParsedBlock, error) {
 	slot, err := tp.cli.GetSlot(ctx, rpc.CommitmentConfirmed)
 	if err != nil {
 		return nil, err
@@ -87,7 +94,24 @@ func (tp *TxParser) ParseLatestBlock(ctx context.Context) ([]*ParsedTx, error) {
 	return tp.ParseBlock(ctx, slot)
 }
 
-func (tp *TxParser) ParseBlock(ctx context.Context, slot uint64) ([]*ParsedTx, error) {
+// ParsedBlock is a parsed block, the transactions failing to parse are in Failed.
+type ParsedBlock struct {
+	Block   uint64 // slot
+	Skipped bool   // no block was produced in the slot, set by ParseBlockRange
+	Txs     []*ParsedTx
+	Failed  []*TxParseError
+}
+
+// TxParseError is a transaction of a block that failed to parse, TxHash is its signature.
+type TxParseError = blockrange.TxParseError
+
+// SetStrict makes the slots with a transaction failing to parse return an error wrapping its *TxParseError.
+// In strict mode an instruction failing to parse fails its transaction instead of being kept as "Unknown".
+func (tp *TxParser) SetStrict(strict bool) {
+	tp.strict = strict
+}
+
+func (tp *TxParser) ParseBlock(ctx context.Context, slot uint64) (*ParsedBlock, error) {
 	res, err := tp.cli.GetBlockWithOpts(ctx, slot, &rpc.GetBlockOpts{
 		MaxSupportedTransactionVersion: rpc.NewTransactionVersion(rpc.MaxSupportedTransactionVersion0),
 		Rewards:                        rpc.NewBoolean(false),
@@ -96,17 +120,102 @@ func (tp *TxParser) ParseBlock(ctx context.Context, slot uint64) ([]*ParsedTx, e
 	if err != nil {
 		return nil, err
 	}
-	parsedTxs := make([]*ParsedTx, 0, len(res.Transactions))
-	slices.Values(res.Transactions)(func(twm rpc.TransactionWithMeta) bool {
+	pb := &ParsedBlock{
+		Block: slot,
+		Txs:   make([]*ParsedTx, 0, len(res.Transactions)),
+	}
+	var strictErr error
+	slices.All(res.Transactions)(func(idx int, twm rpc.TransactionWithMeta) bool {
+		// the transactions of a block have no slot and block time
+		twm.Slot, twm.BlockTime = slot, res.BlockTime
 		parsedTx, err := tp.parseConfirmedTx(twm)
 		if err != nil {
-			zlog.Error("parseConfirmedTx error", zap.Error(err))
+			txErr := &TxParseError{Index: idx, Err: err}
+			if tx, err := twm.GetTransaction(); err == nil && len(tx.Signatures) > 0 {
+				txErr.TxHash = tx.Signatures[0].String()
+			}
+			if tp.strict {
+				strictErr = fmt.Errorf("block %d: %w", slot, txErr)
+				return false
+			}
+			zlog.Error("parseConfirmedTx error", zap.Error(err), zap.String("txHash", txErr.TxHash))
+			pb.Failed = append(pb.Failed, txErr)
 			return true
 		}
-		parsedTxs = append(parsedTxs, parsedTx)
+		pb.Txs = append(pb.Txs, parsedTx)
 		return true
 	})
-	return parsedTxs, nil
+	if strictErr != nil {
+		return nil, strictErr
+	}
+	return pb, nil
+}
+
+// RPC errors of the slots without a block
+// https://github.com/anza-xyz/agave/blob/master/rpc-client-api/src/custom_error.rs
+const (
+	rpcErrSlotSkipped                = -32007
+	rpcErrLongTermStorageSlotSkipped = -32009
+)
+
+// ParseBlockRange parses the blocks from startSlot to endSlot (inclusive), workersOption blocks at a time (8 by default),
+// and yields them in slot order, the skipped slots are yielded with Skipped set. A block failing to parse is yielded
+// with its error and the range goes on, the iteration stops with ctx.Err() when ctx is done.
+func (tp *TxParser) ParseBlockRange(ctx context.Context, startSlot, endSlot uint64, workersOption ...int) iter.Seq2[*ParsedBlock, error] {
+	var workers int
+	if len(workersOption) > 0 {
+		workers = workersOption[0]
+	}
+	return blockrange.Parse(ctx, startSlot, endSlot, workers, func(ctx context.Context, slot uint64) (*ParsedBlock, error) {
+		pb, err := tp.ParseBlock(ctx, slot)
+		var rpcErr *jsonrpc.RPCError
+		if errors.As(err, &rpcErr) && (rpcErr.Code == rpcErrSlotSkipped || rpcErr.Code == rpcErrLongTermStorageSlotSkipped) {
+			return &ParsedBlock{Block: slot, Skipped: true}, nil
+		}
+		if err != nil {
+			return &ParsedBlock{Block: slot}, fmt.Errorf("parse block(%d): %w", slot, err)
+		}
+		return pb, nil
+	})
+}
+
+// ParseTransaction parses a confirmed transaction with its meta, the result is the same as the transaction's entry of ParseBlock.
+// rpc.ErrNotFound is returned for unknown and not yet confirmed transactions.
+func (tp *TxParser) ParseTransaction(ctx context.Context, signature string) (*ParsedTx, error) {
+	sig, err := solana.SignatureFromBase58(signature)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature: %w", err)
+	}
+	twm, err := tp.getTransaction(ctx, sig, rpc.CommitmentConfirmed)
+	if err != nil {
+		return nil, err
+	}
+	return tp.parseConfirmedTx(twm)
+}
+
+// getTransaction reads a transaction in the format of the transactions of getBlock.
+func (tp *TxParser) getTransaction(ctx context.Context, sig solana.Signature, commitment rpc.CommitmentType) (twm rpc.TransactionWithMeta, err error) {
+	res, err := tp.cli.GetTransaction(ctx, sig, &rpc.GetTransactionOpts{
+		Encoding:                       solana.EncodingBase64,
+		Commitment:                     commitment,
+		MaxSupportedTransactionVersion: rpc.NewTransactionVersion(rpc.MaxSupportedTransactionVersion0),
+	})
+	if err != nil {
+		err = fmt.Errorf("get transaction(%s): %w", sig, err)
+		return
+	}
+	if res.Transaction == nil {
+		err = fmt.Errorf("get transaction(%s): %w", sig, rpc.ErrNotFound)
+		return
+	}
+	twm = rpc.TransactionWithMeta{
+		Slot:        res.Slot,
+		BlockTime:   res.BlockTime,
+		Transaction: rpc.DataBytesOrJSONFromBytes(res.Transaction.GetBinary()),
+		Meta:        res.Meta,
+		Version:     res.Version,
+	}
+	return
 }
 
 func (tp *TxParser) parseConfirmedTx(twm rpc.TransactionWithMeta) (ptx *ParsedTx, err error) {
@@ -124,6 +233,10 @@ func (tp *TxParser) parseConfirmedTx(twm rpc.TransactionWithMeta) (ptx *ParsedTx
 		err = errors.New("signatures is empty")
 		return
 	}
+	if err = resolveLookups(tx, twm.Meta); err != nil {
+		err = fmt.Errorf("resolve address lookups: %w", err)
+		return
+	}
 
 	parsedInss := make([]ParsedInstruction, 0, len(tx.Message.Instructions))
 
@@ -135,24 +248,29 @@ func (tp *TxParser) parseConfirmedTx(twm rpc.TransactionWithMeta) (ptx *ParsedTx
 	var (
 		computeUnitPrice uint64
 		computeUnitLimit uint32
+		insErr           error
 	)
 
 	slices.All(tx.Message.Instructions)(func(idx int, ins solana.CompiledInstruction) (next bool) {
 		next = true
 
-		programID := tx.Message.AccountKeys[ins.ProgramIDIndex].String()
+		program, err := tx.Message.Program(ins.ProgramIDIndex)
+		if err != nil {
+			// the instruction would be missing from the parsed transaction
+			insErr = fmt.Errorf("instruction %d program: %w", idx, err)
+			return false
+		}
+		programID := program.String()
 
 		zlog.Debug("parse instruction",
 			zap.String("programID", programID),
 			zap.Uint16("programIDIndex", ins.ProgramIDIndex))
 
+		// the instructions failing to parse are "Unknown" unless strict, an error fails the transaction
 		parsedIns, err := tp.parseInstruction(tx, ins)
 		if err != nil {
-			zlog.Error("parse instruction error",
-				zap.Error(err),
-				zap.String("programID", programID),
-				zap.Uint16("programIDIndex", ins.ProgramIDIndex))
-			return
+			insErr = fmt.Errorf("instruction %d: %w", idx, err)
+			return false
 		}
 
 		if programID == computebudget.ProgramID.String() {
@@ -171,19 +289,15 @@ func (tp *TxParser) parseConfirmedTx(twm rpc.TransactionWithMeta) (ptx *ParsedTx
 				zap.Int("index", idx),
 				zap.Int("count", len(innerIns.Instructions)))
 			parsedInnerInss := make([]ParsedInstruction, 0, len(innerIns.Instructions))
-			for _, innerIns := range innerIns.Instructions {
-				innerProgramID := tx.Message.AccountKeys[innerIns.ProgramIDIndex].String()
+			for innerIdx, innerIns := range innerIns.Instructions {
 				parsedInnerIns, err := tp.parseInstruction(tx, solana.CompiledInstruction{
 					ProgramIDIndex: innerIns.ProgramIDIndex,
 					Accounts:       innerIns.Accounts,
 					Data:           innerIns.Data,
 				})
 				if err != nil {
-					zlog.Error("parse inner instruction error",
-						zap.Error(err),
-						zap.String("programID", innerProgramID),
-						zap.Uint16("programIDIndex", innerIns.ProgramIDIndex))
-					continue
+					insErr = fmt.Errorf("instruction %d inner instruction %d: %w", idx, innerIdx, err)
+					return false
 				}
 				parsedInnerInss = append(parsedInnerInss, parsedInnerIns)
 			}
@@ -193,6 +307,10 @@ func (tp *TxParser) parseConfirmedTx(twm rpc.TransactionWithMeta) (ptx *ParsedTx
 		parsedInss = append(parsedInss, parsedIns)
 		return
 	})
+	if insErr != nil {
+		err = insErr
+		return
+	}
 
 	txStatus := "success"
 	if twm.Meta.Err != nil {
@@ -202,24 +320,124 @@ func (tp *TxParser) parseConfirmedTx(twm rpc.TransactionWithMeta) (ptx *ParsedTx
 	priorityFeeMicroLamports := new(big.Int).Mul(new(big.Int).SetUint64(computeUnitPrice), new(big.Int).SetUint64(uint64(computeUnitLimit)))
 	priorityFee := new(big.Int).Div(priorityFeeMicroLamports, new(big.Int).SetUint64(MicroLamportsPerLamport)).Uint64()
 
+	balanceChanges, err := parseBalanceChanges(tx.Message.AccountKeys, twm.Meta)
+	if err != nil {
+		err = fmt.Errorf("parse balance changes: %w", err)
+		return
+	}
+	tokenBalanceChanges, err := parseTokenBalanceChanges(tx.Message.AccountKeys, twm.Meta)
+	if err != nil {
+		err = fmt.Errorf("parse token balance changes: %w", err)
+		return
+	}
+
+	var timestamp int64
+	if twm.BlockTime != nil {
+		timestamp = twm.BlockTime.Time().UnixMilli()
+	}
+	// the nodes do not report the compute units of the transactions older than compute unit reporting
+	var computeUnitsConsumed uint64
+	if twm.Meta.ComputeUnitsConsumed != nil {
+		computeUnitsConsumed = *twm.Meta.ComputeUnitsConsumed
+	}
+
 	ptx = &ParsedTx{
 		Block:                twm.Slot,
 		Fee:                  twm.Meta.Fee,
-		Timestamp:            twm.BlockTime.Time().UnixMilli(),
+		Timestamp:            timestamp,
 		TxHash:               tx.Signatures[0].String(),
 		Status:               txStatus,
 		Signer:               tx.Message.Signers().ToBase58(),
 		Instructions:         parsedInss,
 		PriorityFee:          priorityFee,
-		ComputeUnitsConsumed: *twm.Meta.ComputeUnitsConsumed,
+		ComputeUnitsConsumed: computeUnitsConsumed,
 		TxVersion:            int(twm.Version),
+		BalanceChanges:       balanceChanges,
+		TokenBalanceChanges:  tokenBalanceChanges,
 	}
 	return
 }
 
+// parseInstruction parses an instruction of a transaction whose address lookups are resolved,
+// the accounts are resolved from the account indexes when the instruction parser does not set them.
 func (tp *TxParser) parseInstruction(tx *solana.Transaction, ins solana.CompiledInstruction) (ParsedInstruction, error) {
-	programID := tx.Message.AccountKeys[ins.ProgramIDIndex].String()
-	return tp.insParserFactory.GetParser(programID)(tx, ins)
+	program, err := tx.Message.Program(ins.ProgramIDIndex)
+	if err != nil {
+		return ParsedInstruction{}, err
+	}
+	programID := program.String()
+	parsedIns, err := tp.insParserFactory.GetParser(programID)(tx, ins)
+	if err != nil {
+		if tp.strict {
+			return ParsedInstruction{}, fmt.Errorf("parse %s instruction: %w", programID, err)
+		}
+		zlog.Error("parse instruction error",
+			zap.Error(err),
+			zap.String("programID", programID),
+			zap.Uint16("programIDIndex", ins.ProgramIDIndex))
+		parsedIns = ParsedInstruction{
+			Name:     "Unknown",
+			Accounts: parsedIns.Accounts,
+			Data:     ins.Data.String(), // base58
+		}
+	}
+	if parsedIns.ProgramID == "" {
+		parsedIns.ProgramID = programID
+	}
+	if len(parsedIns.Accounts) == 0 {
+		if parsedIns.Accounts, err = resolveInstructionAccounts(&tx.Message, ins.Accounts); err != nil {
+			return parsedIns, err
+		}
+	}
+	return parsedIns, nil
+}
+
+// resolveLookups appends the addresses loaded from the address lookup tables of a v0 transaction to its account keys,
+// the instructions index them after the static keys: writable addresses of all the lookups first, then readonly ones.
+func resolveLookups(tx *solana.Transaction, meta *rpc.TransactionMeta) error {
+	msg := &tx.Message
+	if !msg.IsVersioned() || msg.NumLookups() == 0 || msg.IsResolved() {
+		return nil
+	}
+	writable, readonly := meta.LoadedAddresses.Writable, meta.LoadedAddresses.ReadOnly
+	if len(writable) != msg.NumWritableLookups() || len(writable)+len(readonly) != msg.NumLookups() {
+		return fmt.Errorf("loaded addresses (%d writable, %d readonly) do not match %d lookups (%d writable)",
+			len(writable), len(readonly), msg.NumLookups(), msg.NumWritableLookups())
+	}
+	return msg.ResolveLookupsWith(writable, readonly)
+}
+
+// resolveInstructionAccounts resolves the account indexes of an instruction with the account keys of a resolved message.
+func resolveInstructionAccounts(msg *solana.Message, accountIndexes []uint16) ([]ParsedInstructionAccount, error) {
+	if len(accountIndexes) == 0 {
+		return nil, nil
+	}
+	numSigners := int(msg.Header.NumRequiredSignatures)
+	numStatic := len(msg.AccountKeys)
+	if msg.IsResolved() {
+		numStatic -= msg.NumLookups()
+	}
+	accs := make([]ParsedInstructionAccount, 0, len(accountIndexes))
+	for _, accIdx := range accountIndexes {
+		idx := int(accIdx)
+		if idx >= len(msg.AccountKeys) {
+			return nil, fmt.Errorf("account index %d out of %d accounts", idx, len(msg.AccountKeys))
+		}
+		acc := ParsedInstructionAccount{
+			Address:  msg.AccountKeys[idx].String(),
+			IsSigner: idx < numSigners,
+		}
+		switch {
+		case idx < numSigners:
+			acc.IsWritable = idx < numSigners-int(msg.Header.NumReadonlySignedAccounts)
+		case idx < numStatic:
+			acc.IsWritable = idx < numStatic-int(msg.Header.NumReadonlyUnsignedAccounts)
+		default:
+			acc.IsWritable = idx-numStatic < msg.NumWritableLookups()
+		}
+		accs = append(accs, acc)
+	}
+	return accs, nil
 }
 
 func parseInstructionAccounts(accs []*solana.AccountMeta) []ParsedInstructionAccount {
@@ -238,13 +456,32 @@ func parseInstructionAccounts(accs []*solana.AccountMeta) []ParsedInstructionAcc
 	return parsedAccs
 }
 
-type instructionsParser func(tx *solana.Transaction, ins solana.CompiledInstruction) (ParsedInstruction, error)
+// InstructionParser parses the instructions of a program. tx has its address lookups resolved: the account indexes of ins
+// are indexes of tx.Message.AccountKeys. The accounts are resolved from the indexes when the parser does not set them,
+// and the program id of the instruction is used when the parser does not set it.
+// An instruction the parser returns an error for is kept as "Unknown" with its base58 data and the accounts the parser returned,
+// unless the TxParser is strict.
+type InstructionParser func(tx *solana.Transaction, ins solana.CompiledInstruction) (ParsedInstruction, error)
 
 type instructionsParserFactory struct {
-	parsers map[string]instructionsParser
+	parsers map[string]InstructionParser
+}
+
+// RegisterInstructionParser registers the instruction parser of a program, it replaces the built-in parser of the program if any.
+// The instructions of the programs without parser are parsed with the name "Unknown" and their base58 data.
+// Register the parsers before parsing, registering is not safe during parsing.
+func (tp *TxParser) RegisterInstructionParser(programID string, parser InstructionParser) error {
+	if _, err := solana.PublicKeyFromBase58(programID); err != nil {
+		return fmt.Errorf("invalid program id: %w", err)
+	}
+	if parser == nil {
+		return errors.New("instruction parser is nil")
+	}
+	tp.insParserFactory.parsers[programID] = parser
+	return nil
 }
 
-func (f *instructionsParserFactory) GetParser(programID string) instructionsParser {
+func (f *instructionsParserFactory) GetParser(programID string) InstructionParser {
 	parser, ok := f.parsers[programID]
 	if !ok {
 		return func(tx *solana.Transaction, ins solana.CompiledInstruction) (ParsedInstruction, error) {
@@ -260,7 +497,7 @@ func (f *instructionsParserFactory) GetParser(programID string) instructionsPars
 
 func newInstructionsParserFactory() instructionsParserFactory {
 	return instructionsParserFactory{
-		parsers: map[string]instructionsParser{
+		parsers: map[string]InstructionParser{
 			system.ProgramID.String(): func(tx *solana.Transaction, ins solana.CompiledInstruction) (ParsedInstruction, error) {
 				var insData system.Instruction
 				err := insData.UnmarshalWithDecoder(bin.NewBinDecoder(ins.Data))
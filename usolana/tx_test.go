@@ -2,9 +2,12 @@ package usolana
 
 import (
 	"encoding/json"
+	"errors"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/system"
 	"github.com/gagliardetto/solana-go/rpc"
 	"github.com/stretchr/testify/assert"
 )
@@ -33,4 +36,252 @@ func TestParseConfirmTx(t *testing.T) {
 	t.Log("parsed tx json:", string(ptxJson))
 }
 
+func TestParseTransaction(t *testing.T) {
+	payer := solana.NewWallet()
+	tx := newSignedTransferTx(t, payer)
+	signature := tx.Signatures[0].String()
+	m := &mockSolanaRPC{tx: tx}
+	srv := httptest.NewServer(m)
+	defer srv.Close()
+	tp := NewTxParser(srv.URL)
+
+	_, err := tp.ParseTransaction(t.Context(), signature)
+	assert.ErrorIs(t, err, rpc.ErrNotFound)
+
+	m.set(func(m *mockSolanaRPC) { m.status = rpc.ConfirmationStatusConfirmed })
+	ptx, err := tp.ParseTransaction(t.Context(), signature)
+	assert.NoError(t, err)
+	assert.Equal(t, signature, ptx.TxHash)
+	assert.Equal(t, uint64(100), ptx.Block)
+	assert.Equal(t, int64(1700000000000), ptx.Timestamp)
+	assert.Equal(t, []string{payer.PublicKey().String()}, ptx.Signer)
+	assert.Equal(t, uint64(150), ptx.ComputeUnitsConsumed)
+	assert.Equal(t, -1, ptx.TxVersion)
+	assert.Equal(t, []BalanceChange{
+		{Account: payer.PublicKey().String(), Pre: 10, Post: 5, Delta: -5},
+		{Account: tx.Message.AccountKeys[1].String(), Pre: 0, Post: 5, Delta: 5},
+	}, ptx.BalanceChanges)
+	assert.Empty(t, ptx.TokenBalanceChanges)
+
+	_, err = tp.ParseTransaction(t.Context(), "invalid")
+	assert.Error(t, err)
+}
+
+func TestParseConfirmedTxWithoutComputeUnits(t *testing.T) {
+	payer := solana.NewWallet()
+	twm := newAnchorTestTx(t, payer, solana.NewWallet().PublicKey(), nil, []byte{1})
+	// the old transactions have no compute units consumed
+	twm.Meta.ComputeUnitsConsumed = nil
+
+	ptx, err := NewTxParser(rpc.LocalNet_RPC).parseConfirmedTx(twm)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), ptx.ComputeUnitsConsumed)
+	assert.Equal(t, "Unknown", ptx.Instructions[0].Name)
+}
+
+func TestParseV0Transaction(t *testing.T) {
+	payer := solana.NewWallet()
+	recipient, table := solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey()
+	tx, err := solana.NewTransaction(
+		[]solana.Instruction{system.NewTransferInstruction(5, payer.PublicKey(), recipient).Build()},
+		solana.Hash{1},
+		solana.TransactionPayer(payer.PublicKey()),
+		solana.TransactionAddressTables(map[solana.PublicKey]solana.PublicKeySlice{table: {solana.NewWallet().PublicKey(), recipient}}),
+	)
+	assert.NoError(t, err)
+	_, err = tx.Sign(func(solana.PublicKey) *solana.PrivateKey { return &payer.PrivateKey })
+	assert.NoError(t, err)
+	txBytes, err := tx.MarshalBinary()
+	assert.NoError(t, err)
+
+	cu := uint64(150)
+	twm := rpc.TransactionWithMeta{
+		Slot:        100,
+		Transaction: rpc.DataBytesOrJSONFromBytes(txBytes),
+		Meta: &rpc.TransactionMeta{
+			Fee:                  5000,
+			PreBalances:          []uint64{10, 1, 0},
+			PostBalances:         []uint64{5, 1, 5},
+			LoadedAddresses:      rpc.LoadedAddresses{Writable: solana.PublicKeySlice{recipient}},
+			ComputeUnitsConsumed: &cu,
+		},
+		Version: 0,
+	}
+	tp := NewTxParser(rpc.LocalNet_RPC)
+	ptx, err := tp.parseConfirmedTx(twm)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, ptx.TxVersion)
+	if assert.Len(t, ptx.Instructions, 1) {
+		ins := ptx.Instructions[0]
+		assert.Equal(t, solana.SystemProgramID.String(), ins.ProgramID)
+		assert.Equal(t, "Transfer", ins.Name)
+		assert.Equal(t, []ParsedInstructionAccount{
+			{Address: payer.PublicKey().String(), IsWritable: true, IsSigner: true},
+			{Address: recipient.String(), IsWritable: true},
+		}, ins.Accounts)
+	}
+	assert.Equal(t, []BalanceChange{
+		{Account: payer.PublicKey().String(), Pre: 10, Post: 5, Delta: -5},
+		{Account: recipient.String(), Pre: 0, Post: 5, Delta: 5},
+	}, ptx.BalanceChanges)
+
+	// the loaded addresses do not match the lookups
+	twm.Meta.LoadedAddresses = rpc.LoadedAddresses{}
+	_, err = tp.parseConfirmedTx(twm)
+	assert.Error(t, err)
+
+	// a program index out of the accounts fails the transaction instead of dropping the instruction
+	twm.Meta.LoadedAddresses = rpc.LoadedAddresses{Writable: solana.PublicKeySlice{recipient}}
+	tx.Message.Instructions[0].ProgramIDIndex = 9
+	txBytes, err = tx.MarshalBinary()
+	assert.NoError(t, err)
+	twm.Transaction = rpc.DataBytesOrJSONFromBytes(txBytes)
+	_, err = tp.parseConfirmedTx(twm)
+	assert.ErrorContains(t, err, "instruction 0 program")
+}
+
+func TestRegisterInstructionParser(t *testing.T) {
+	payer := solana.NewWallet()
+	program, counter := solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey()
+	tx, err := solana.NewTransaction(
+		[]solana.Instruction{solana.NewInstruction(program, solana.AccountMetaSlice{solana.Meta(counter).WRITE()}, []byte{1, 42})},
+		solana.Hash{1},
+		solana.TransactionPayer(payer.PublicKey()),
+	)
+	assert.NoError(t, err)
+	_, err = tx.Sign(func(solana.PublicKey) *solana.PrivateKey { return &payer.PrivateKey })
+	assert.NoError(t, err)
+	txBytes, err := tx.MarshalBinary()
+	assert.NoError(t, err)
+	cu := uint64(150)
+	twm := rpc.TransactionWithMeta{
+		Transaction: rpc.DataBytesOrJSONFromBytes(txBytes),
+		Meta:        &rpc.TransactionMeta{ComputeUnitsConsumed: &cu},
+		Version:     rpc.LegacyTransactionVersion,
+	}
+
+	tp := NewTxParser(rpc.LocalNet_RPC)
+	ptx, err := tp.parseConfirmedTx(twm)
+	assert.NoError(t, err)
+	assert.Equal(t, "Unknown", ptx.Instructions[0].Name)
+
+	assert.Error(t, tp.RegisterInstructionParser("invalid", func(*solana.Transaction, solana.CompiledInstruction) (ParsedInstruction, error) {
+		return ParsedInstruction{}, nil
+	}))
+	assert.Error(t, tp.RegisterInstructionParser(program.String(), nil))
+	assert.NoError(t, tp.RegisterInstructionParser(program.String(), func(_ *solana.Transaction, ins solana.CompiledInstruction) (ParsedInstruction, error) {
+		return ParsedInstruction{TypeID: uint32(ins.Data[0]), Name: "Increment", Data: ins.Data[1]}, nil
+	}))
+	ptx, err = tp.parseConfirmedTx(twm)
+	assert.NoError(t, err)
+	assert.Equal(t, ParsedInstruction{
+		ProgramID: program.String(),
+		TypeID:    1,
+		Name:      "Increment",
+		Accounts:  []ParsedInstructionAccount{{Address: counter.String(), IsWritable: true}},
+		Data:      byte(42),
+	}, ptx.Instructions[0])
+
+	// other parsers are not affected
+	ptx, err = NewTxParser(rpc.LocalNet_RPC).parseConfirmedTx(twm)
+	assert.NoError(t, err)
+	assert.Equal(t, "Unknown", ptx.Instructions[0].Name)
+}
+
+func TestParseInstructionError(t *testing.T) {
+	payer := solana.NewWallet()
+	program, counter := solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey()
+	twm := newAnchorTestTx(t, payer, program, solana.AccountMetaSlice{solana.Meta(counter).WRITE()}, []byte{1, 42})
+	// an inner instruction of the program the parser fails on, the program is the third account key
+	twm.Meta.InnerInstructions = []rpc.InnerInstruction{{
+		Index:        0,
+		Instructions: []rpc.CompiledInstruction{{ProgramIDIndex: 2, Accounts: []uint16{1}, Data: solana.Base58{0xff}}},
+	}}
+
+	tp := NewTxParser(rpc.LocalNet_RPC)
+	assert.NoError(t, tp.RegisterInstructionParser(program.String(), func(_ *solana.Transaction, ins solana.CompiledInstruction) (ParsedInstruction, error) {
+		if ins.Data[0] != 1 {
+			return ParsedInstruction{}, errors.New("unknown instruction type")
+		}
+		return ParsedInstruction{TypeID: 1, Name: "Increment", Data: ins.Data[1]}, nil
+	}))
+
+	ptx, err := tp.parseConfirmedTx(twm)
+	assert.NoError(t, err)
+	if assert.Len(t, ptx.Instructions, 1) {
+		assert.Equal(t, "Increment", ptx.Instructions[0].Name)
+		assert.Equal(t, []ParsedInstruction{{
+			ProgramID: program.String(),
+			Name:      "Unknown",
+			Accounts:  []ParsedInstructionAccount{{Address: counter.String(), IsWritable: true}},
+			Data:      solana.Base58{0xff}.String(),
+		}}, ptx.Instructions[0].InnerInstructions)
+	}
+
+	tp.SetStrict(true)
+	_, err = tp.parseConfirmedTx(twm)
+	assert.ErrorContains(t, err, "instruction 0 inner instruction 0")
+
+	twm.Meta.InnerInstructions = nil
+	ptx, err = tp.parseConfirmedTx(twm)
+	assert.NoError(t, err)
+	assert.Equal(t, "Increment", ptx.Instructions[0].Name)
+
+	twm = newAnchorTestTx(t, payer, program, solana.AccountMetaSlice{solana.Meta(counter).WRITE()}, []byte{2})
+	_, err = tp.parseConfirmedTx(twm)
+	assert.ErrorContains(t, err, "unknown instruction type")
+}
+
+func TestParseBlockRange(t *testing.T) {
+	payer := solana.NewWallet()
+	tx := newSignedTransferTx(t, payer)
+	srv := httptest.NewServer(&mockSolanaRPC{tx: tx})
+	defer srv.Close()
+	tp := NewTxParser(srv.URL)
+
+	var slots []uint64
+	for pb, err := range tp.ParseBlockRange(t.Context(), 99, 102, 2) {
+		assert.NoError(t, err)
+		slots = append(slots, pb.Block)
+		switch pb.Block {
+		case 100:
+			assert.Len(t, pb.Txs, 1)
+			assert.Equal(t, tx.Signatures[0].String(), pb.Txs[0].TxHash)
+			assert.Equal(t, uint64(100), pb.Txs[0].Block)
+			assert.Equal(t, int64(1700000000000), pb.Txs[0].Timestamp)
+		case 101:
+			assert.True(t, pb.Skipped)
+		default:
+			assert.False(t, pb.Skipped)
+			assert.Empty(t, pb.Txs)
+		}
+	}
+	assert.Equal(t, []uint64{99, 100, 101, 102}, slots)
+}
+
+func TestParseBlockFailed(t *testing.T) {
+	tx := newSignedTransferTx(t, solana.NewWallet())
+	signature := tx.Signatures[0].String()
+	srv := httptest.NewServer(&mockSolanaRPC{tx: tx, brokenTx: true})
+	defer srv.Close()
+	tp := NewTxParser(srv.URL)
+
+	pb, err := tp.ParseBlock(t.Context(), 100)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(100), pb.Block)
+	assert.Len(t, pb.Txs, 1)
+	if assert.Len(t, pb.Failed, 1) {
+		assert.Equal(t, 0, pb.Failed[0].Index)
+		assert.Equal(t, signature, pb.Failed[0].TxHash)
+		assert.Error(t, pb.Failed[0].Err)
+	}
+
+	tp.SetStrict(true)
+	_, err = tp.ParseBlock(t.Context(), 100)
+	var txErr *TxParseError
+	if assert.ErrorAs(t, err, &txErr) {
+		assert.Equal(t, signature, txErr.TxHash)
+	}
+}
+
 // TODO: add more tests
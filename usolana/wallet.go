@@ -6,6 +6,10 @@ import (
 	"crypto/rand"
 	"fmt"
 
+	"github.com/15ho/wallet-utils-go/bip39"
+	"github.com/15ho/wallet-utils-go/keystore"
+	"github.com/15ho/wallet-utils-go/signer"
+	"github.com/15ho/wallet-utils-go/slip10"
 	"github.com/gagliardetto/solana-go"
 	tokenacc "github.com/gagliardetto/solana-go/programs/associated-token-account"
 	computebudget "github.com/gagliardetto/solana-go/programs/compute-budget"
@@ -25,6 +29,41 @@ func CreateWalletAccount() (privateKeyBase58, address string, err error) {
 	return
 }
 
+// CreateWalletAccountFromMnemonic derives the account at m/44'/501'/{accountIndex}'/0', the same as Phantom and Solflare.
+// The passphrase is the optional BIP-0039 passphrase, pass "" if none.
+func CreateWalletAccountFromMnemonic(mnemonic, passphrase string, accountIndex uint32) (privateKeyBase58, address string, err error) {
+	if err = bip39.Validate(mnemonic); err != nil {
+		err = fmt.Errorf("invalid mnemonic: %w", err)
+		return
+	}
+	seed := bip39.CreateSeedFromMnemonic(mnemonic, passphrase)
+	pk, err := PrivateKeyFromSeed(seed, accountIndex)
+	if err != nil {
+		return
+	}
+	privateKeyBase58 = pk.String()
+	address = pk.PublicKey().String()
+	return
+}
+
+// PrivateKeyFromSeed derives the private key at m/44'/501'/{accountIndex}'/0' from a BIP-0039 seed using SLIP-0010.
+func PrivateKeyFromSeed(seed []byte, accountIndex uint32) (solana.PrivateKey, error) {
+	return PrivateKeyFromSeedWithPath(seed, fmt.Sprintf(DerivationPathFormat, accountIndex))
+}
+
+// PrivateKeyFromSeedWithPath derives the private key at a custom SLIP-0010 path, e.g. "m/44'/501'/0'" used by Solana CLI.
+func PrivateKeyFromSeedWithPath(seed []byte, path string) (solana.PrivateKey, error) {
+	masterKey, err := slip10.NewMasterKey(seed)
+	if err != nil {
+		return nil, fmt.Errorf("new master key: %w", err)
+	}
+	key, err := masterKey.DerivePath(path)
+	if err != nil {
+		return nil, err
+	}
+	return solana.PrivateKey(key.PrivateKey()), nil
+}
+
 func WalletAddressFromPrivateKey(privateKeyBase58 string) (address string, err error) {
 	privateKey, err := solana.PrivateKeyFromBase58(privateKeyBase58)
 	if err != nil {
@@ -36,8 +75,8 @@ func WalletAddressFromPrivateKey(privateKeyBase58 string) (address string, err e
 type WalletClient struct {
 	cli *rpc.Client
 
-	privateKey solana.PrivateKey
-	account    solana.PublicKey
+	signer  signer.Ed25519Signer
+	account solana.PublicKey
 }
 
 func NewWalletClient(endpoint, privateKeyBase58 string) (*WalletClient, error) {
@@ -45,10 +84,20 @@ func NewWalletClient(endpoint, privateKeyBase58 string) (*WalletClient, error) {
 	if err != nil {
 		return nil, err
 	}
+	s, err := signer.NewLocalEd25519Signer(ed25519.PrivateKey(privateKey))
+	if err != nil {
+		return nil, err
+	}
+	return NewWalletClientFromSigner(endpoint, s)
+}
+
+// NewWalletClientFromSigner creates a WalletClient whose transactions are signed by s,
+// the private key is never touched by the WalletClient.
+func NewWalletClientFromSigner(endpoint string, s signer.Ed25519Signer) (*WalletClient, error) {
 	return &WalletClient{
-		cli:        rpc.New(endpoint),
-		privateKey: privateKey,
-		account:    privateKey.PublicKey(), // NOTE: Check if the provided `b` is on the ed25519 curve.
+		cli:     rpc.New(endpoint),
+		signer:  s,
+		account: solana.PublicKeyFromBytes(s.PublicKey()),
 	}, nil
 }
 
@@ -60,6 +109,41 @@ func NewDevnetWalletClient(privateKeyBase58 string) (*WalletClient, error) {
 	return NewWalletClient(rpc.DevNet_RPC, privateKeyBase58)
 }
 
+// NewWalletClientFromKeystore opens a keystore v3 JSON file with the passphrase, see keystore.WriteKeyFile.
+// The keystore stores the 64 bytes ed25519 private key (seed || public key).
+func NewWalletClientFromKeystore(endpoint, keystorePath, passphrase string) (*WalletClient, error) {
+	privateKey, err := keystore.ReadKeyFile(keystorePath, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("open keystore: %w", err)
+	}
+	s, err := signer.NewLocalEd25519Signer(ed25519.PrivateKey(privateKey))
+	if err != nil {
+		return nil, err
+	}
+	return NewWalletClientFromSigner(endpoint, s)
+}
+
+// signTx signs the transaction with the signer, the wallet account must be the only signer of the transaction.
+func (wc *WalletClient) signTx(ctx context.Context, tx *solana.Transaction) error {
+	signers := tx.Message.Signers()
+	if len(signers) != 1 || !signers[0].Equals(wc.account) {
+		return fmt.Errorf("tx sign: unexpected signers %v", signers)
+	}
+	message, err := tx.Message.MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("tx sign: marshal message: %w", err)
+	}
+	signature, err := wc.signer.SignMessage(ctx, message)
+	if err != nil {
+		return fmt.Errorf("tx sign: %w", err)
+	}
+	if !ed25519.Verify(wc.account.Bytes(), message, signature) {
+		return fmt.Errorf("tx sign: %w", signer.ErrSignerMismatch)
+	}
+	tx.Signatures = []solana.Signature{solana.SignatureFromBytes(signature)}
+	return nil
+}
+
 func (wc *WalletClient) buildTxTransferSOL(ctx context.Context, toAddress string, amount uint64, priorityFeeOption ...TxPriorityFee) (tx *solana.Transaction, err error) {
 	to, err := solana.PublicKeyFromBase58(toAddress)
 	if err != nil {
@@ -96,17 +180,7 @@ func (wc *WalletClient) buildTxTransferSOL(ctx context.Context, toAddress string
 		return
 	}
 
-	_, err = tx.Sign(
-		func(key solana.PublicKey) *solana.PrivateKey {
-			if wc.account.Equals(key) {
-				return &wc.privateKey
-			}
-			return nil
-		},
-	)
-	if err != nil {
-		err = fmt.Errorf("tx sign: %w", err)
-	}
+	err = wc.signTx(ctx, tx)
 	return
 }
 
@@ -228,17 +302,7 @@ func (wc *WalletClient) buildTxTransferSPLToken(ctx context.Context, tokenAddres
 		err = fmt.Errorf("new tx: %w", err)
 		return
 	}
-	_, err = tx.Sign(
-		func(key solana.PublicKey) *solana.PrivateKey {
-			if wc.account.Equals(key) {
-				return &wc.privateKey
-			}
-			return nil
-		},
-	)
-	if err != nil {
-		err = fmt.Errorf("tx sign: %w", err)
-	}
+	err = wc.signTx(ctx, tx)
 	return
 }
 
@@ -1,10 +1,15 @@
 package usolana
 
 import (
+	"context"
+	"crypto/ed25519"
 	"errors"
 	"math/big"
 	"testing"
 
+	"github.com/15ho/wallet-utils-go/signer"
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/system"
 	"github.com/gagliardetto/solana-go/rpc"
 	"github.com/stretchr/testify/assert"
 )
@@ -21,6 +26,68 @@ func TestCreateWalletAccount(t *testing.T) {
 	assert.Equal(t, addr, addr2)
 }
 
+func TestCreateWalletAccountFromMnemonic(t *testing.T) {
+	// addresses generated by Phantom
+	mnemonic := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	for _, tc := range []struct {
+		accountIndex uint32
+		address      string
+	}{
+		{0, "HAgk14JpMQLgt6rVgv7cBQFJWFto5Dqxi472uT3DKpqk"},
+	} {
+		privateKeyBase58, address, err := CreateWalletAccountFromMnemonic(mnemonic, "", tc.accountIndex)
+		assert.NoError(t, err)
+		assert.Equal(t, tc.address, address)
+
+		address2, err := WalletAddressFromPrivateKey(privateKeyBase58)
+		assert.NoError(t, err)
+		assert.Equal(t, tc.address, address2)
+	}
+
+	_, address, err := CreateWalletAccountFromMnemonic(mnemonic, "TREZOR", 0)
+	assert.NoError(t, err)
+	assert.NotEqual(t, "HAgk14JpMQLgt6rVgv7cBQFJWFto5Dqxi472uT3DKpqk", address)
+
+	_, _, err = CreateWalletAccountFromMnemonic("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon", "", 0)
+	assert.Error(t, err)
+}
+
+func TestSignTx(t *testing.T) {
+	_, privateKey, err := ed25519.GenerateKey(nil)
+	assert.NoError(t, err)
+	s, err := signer.NewLocalEd25519Signer(privateKey)
+	assert.NoError(t, err)
+	wc, err := NewWalletClientFromSigner(rpc.DevNet_RPC, s)
+	assert.NoError(t, err)
+
+	tx, err := solana.NewTransaction(
+		[]solana.Instruction{system.NewTransferInstruction(1, wc.account, solana.SystemProgramID).Build()},
+		solana.Hash{},
+		solana.TransactionPayer(wc.account),
+	)
+	assert.NoError(t, err)
+	err = wc.signTx(context.Background(), tx)
+	assert.NoError(t, err)
+	assert.NoError(t, tx.VerifySignatures())
+
+	// same as signing with the private key directly
+	signatures, err := tx.Sign(func(key solana.PublicKey) *solana.PrivateKey {
+		pk := solana.PrivateKey(privateKey)
+		return &pk
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, signatures, tx.Signatures)
+
+	// another account must not be a signer
+	tx, err = solana.NewTransaction(
+		[]solana.Instruction{system.NewTransferInstruction(1, wc.account, solana.SystemProgramID).Build()},
+		solana.Hash{},
+		solana.TransactionPayer(solana.NewWallet().PublicKey()),
+	)
+	assert.NoError(t, err)
+	assert.Error(t, wc.signTx(context.Background(), tx))
+}
+
 func TestWalletClient(t *testing.T) {
 	if Acc1PrivateKeyBase58 == "" {
 		t.Skip("ACC1PK58 env var is not set")
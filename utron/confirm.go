@@ -0,0 +1,152 @@
+package utron
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/15ho/wallet-utils-go/internal/zlog"
+	"github.com/fbsobreira/gotron-sdk/pkg/client"
+	"github.com/fbsobreira/gotron-sdk/pkg/proto/api"
+	"go.uber.org/zap"
+)
+
+// ErrTxExpired is returned when a transaction passed its expiration time without being included, it will never be included.
+var ErrTxExpired = errors.New("transaction expired")
+
+// ConfirmationOptions configures a ConfirmationTracker.
+type ConfirmationOptions struct {
+	// PollInterval is the time between two polls of the node.
+	PollInterval time.Duration
+}
+
+// DefaultConfirmationOptions are used when a ConfirmationTracker is created without options, a block is produced every 3 seconds.
+var DefaultConfirmationOptions = ConfirmationOptions{
+	PollInterval: 3 * time.Second,
+}
+
+// ConfirmationTracker waits for sent transactions to be solidified (irreversible),
+// a block is solidified when 2/3 of the super representatives produced blocks on top of it.
+type ConfirmationTracker struct {
+	cli  *client.GrpcClient
+	tp   *TxParser
+	opts ConfirmationOptions
+}
+
+// ConfirmationTracker returns a confirmation tracker using the node of the TxParser,
+// the zero fields of the options are set from DefaultConfirmationOptions.
+func (tp *TxParser) ConfirmationTracker(optsOption ...ConfirmationOptions) *ConfirmationTracker {
+	opts := DefaultConfirmationOptions
+	if len(optsOption) > 0 {
+		opts = optsOption[0]
+	}
+	if opts.PollInterval == 0 {
+		opts.PollInterval = DefaultConfirmationOptions.PollInterval
+	}
+	return &ConfirmationTracker{cli: tp.cli, tp: tp, opts: opts}
+}
+
+// ConfirmationTracker returns a confirmation tracker of the transactions sent by the WalletClient.
+func (wc *WalletClient) ConfirmationTracker(optsOption ...ConfirmationOptions) *ConfirmationTracker {
+	return (&TxParser{cli: wc.cli}).ConfirmationTracker(optsOption...)
+}
+
+// Wait polls the node until the block of the transaction is solidified and returns it parsed like TxParser.ParseBlock,
+// a failed transaction is returned with the status "fail".
+// ErrTxExpired is returned when the head block passes the expiration of the transaction and the transaction was not included.
+// Without expirationOption (raw_data.expiration of the transaction, milliseconds),
+// the transaction expires TxExpiration after the head block of the call.
+func (ct *ConfirmationTracker) Wait(ctx context.Context, txHash string, expirationOption ...int64) (ptx *ParsedTx, err error) {
+	txID, err := hex.DecodeString(txHash)
+	if err != nil {
+		err = fmt.Errorf("invalid tx hash: %w", err)
+		return
+	}
+	var expiration int64
+	if len(expirationOption) > 0 {
+		expiration = expirationOption[0]
+	} else {
+		var headTime int64
+		if headTime, err = ct.headBlockTime(ctx); err != nil {
+			return
+		}
+		expiration = headTime + TxExpiration.Milliseconds()
+	}
+
+	ticker := time.NewTicker(ct.opts.PollInterval)
+	defer ticker.Stop()
+	for {
+		var confirmed bool
+		if ptx, confirmed, err = ct.poll(ctx, txID, expiration); err != nil || confirmed {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			err = ctx.Err()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (ct *ConfirmationTracker) poll(ctx context.Context, txID []byte, expiration int64) (ptx *ParsedTx, confirmed bool, err error) {
+	// read before the transaction info: the transaction was not included when the head passed the expiration
+	headTime, err := ct.headBlockTime(ctx)
+	if err != nil {
+		return
+	}
+	txInfo, err := ct.cli.Client.GetTransactionInfoById(ctx, &api.BytesMessage{Value: txID})
+	if err != nil {
+		err = fmt.Errorf("get transaction info: %w", err)
+		return
+	}
+	if !bytes.Equal(txInfo.GetId(), txID) {
+		// not included yet
+		if headTime > expiration {
+			err = fmt.Errorf("%w: %x, head block time %d > expiration %d", ErrTxExpired, txID, headTime, expiration)
+		}
+		return
+	}
+
+	nodeInfo, err := ct.cli.Client.GetNodeInfo(ctx, &api.EmptyMessage{})
+	if err != nil {
+		err = fmt.Errorf("get node info: %w", err)
+		return
+	}
+	solidBlockNum, err := parseSolidityBlockNum(nodeInfo.GetSolidityBlock())
+	if err != nil {
+		return
+	}
+	if txInfo.BlockNumber > solidBlockNum {
+		zlog.Debug("waiting for solidified block",
+			zap.String("txHash", hex.EncodeToString(txID)),
+			zap.Int64("block", txInfo.BlockNumber),
+			zap.Int64("solidBlock", solidBlockNum))
+		return
+	}
+
+	if ptx, err = ct.tp.parseTxByInfo(ctx, txInfo); err != nil {
+		return
+	}
+	confirmed = true
+	return
+}
+
+func (ct *ConfirmationTracker) headBlockTime(ctx context.Context) (int64, error) {
+	block, err := ct.cli.Client.GetNowBlock2(ctx, &api.EmptyMessage{})
+	if err != nil {
+		return 0, fmt.Errorf("get now block: %w", err)
+	}
+	return block.GetBlockHeader().GetRawData().GetTimestamp(), nil
+}
+
+// parseSolidityBlockNum parses the solidity block of the node info, e.g. "Num:123,ID:0000...".
+func parseSolidityBlockNum(solidityBlock string) (num int64, err error) {
+	if _, err = fmt.Sscanf(solidityBlock, "Num:%d,", &num); err != nil {
+		err = fmt.Errorf("invalid solidity block %q: %w", solidityBlock, err)
+	}
+	return
+}
@@ -0,0 +1,179 @@
+package utron
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fbsobreira/gotron-sdk/pkg/address"
+	"github.com/fbsobreira/gotron-sdk/pkg/client"
+	"github.com/fbsobreira/gotron-sdk/pkg/proto/api"
+	"github.com/fbsobreira/gotron-sdk/pkg/proto/core"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// fakeWalletServer serves the Wallet methods used by the TxParser and the ConfirmationTracker for one transaction.
+type fakeWalletServer struct {
+	api.UnimplementedWalletServer
+
+	mu       sync.Mutex
+	headNum  int64
+	headTime int64
+	solidNum int64
+	tx       *core.Transaction
+	txInfo   *core.TransactionInfo // nil: not included
+	noInfos  bool                  // the block infos miss the transaction info
+}
+
+func (s *fakeWalletServer) GetNowBlock2(context.Context, *api.EmptyMessage) (*api.BlockExtention, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.block(s.headNum), nil
+}
+
+func (s *fakeWalletServer) GetBlockByNum2(_ context.Context, req *api.NumberMessage) (*api.BlockExtention, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if req.Num > s.headNum {
+		return &api.BlockExtention{}, nil
+	}
+	return s.block(req.Num), nil
+}
+
+func (s *fakeWalletServer) GetTransactionInfoByBlockNum(_ context.Context, req *api.NumberMessage) (*api.TransactionInfoList, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	list := &api.TransactionInfoList{}
+	if s.txInfo != nil && s.txInfo.BlockNumber == req.Num && !s.noInfos {
+		list.TransactionInfo = append(list.TransactionInfo, s.txInfo)
+	}
+	return list, nil
+}
+
+// block returns the block of the given height, the transaction is in the block of its info.
+func (s *fakeWalletServer) block(num int64) *api.BlockExtention {
+	block := &api.BlockExtention{BlockHeader: &core.BlockHeader{RawData: &core.BlockHeaderRaw{Number: num, Timestamp: s.headTime}}}
+	if s.txInfo != nil && s.txInfo.BlockNumber == num {
+		block.Transactions = append(block.Transactions, &api.TransactionExtention{Transaction: s.tx, Txid: s.txInfo.Id})
+	}
+	return block
+}
+
+func (s *fakeWalletServer) GetNodeInfo(context.Context, *api.EmptyMessage) (*core.NodeInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return &core.NodeInfo{SolidityBlock: fmt.Sprintf("Num:%d,ID:00", s.solidNum)}, nil
+}
+
+func (s *fakeWalletServer) GetTransactionInfoById(context.Context, *api.BytesMessage) (*core.TransactionInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.txInfo == nil {
+		return &core.TransactionInfo{}, nil
+	}
+	return s.txInfo, nil
+}
+
+func (s *fakeWalletServer) GetTransactionById(context.Context, *api.BytesMessage) (*core.Transaction, error) {
+	return s.tx, nil
+}
+
+func (s *fakeWalletServer) set(f func(s *fakeWalletServer)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f(s)
+}
+
+// newFakeTransferTx returns a transfer of 1 TRX from from to to, and its id.
+func newFakeTransferTx(t *testing.T, from, to string) (tx *core.Transaction, txID []byte) {
+	fromAddr, err := address.Base58ToAddress(from)
+	assert.NoError(t, err)
+	toAddr, err := address.Base58ToAddress(to)
+	assert.NoError(t, err)
+	transfer, err := anypb.New(&core.TransferContract{
+		OwnerAddress: fromAddr.Bytes(),
+		ToAddress:    toAddr.Bytes(),
+		Amount:       SunPerTRX,
+	})
+	assert.NoError(t, err)
+	tx = &core.Transaction{
+		RawData: &core.TransactionRaw{
+			Contract:   []*core.Transaction_Contract{{Type: core.Transaction_Contract_TransferContract, Parameter: transfer}},
+			Expiration: 1700000060000,
+		},
+		Ret: []*core.Transaction_Result{{ContractRet: core.Transaction_Result_SUCCESS}},
+	}
+	rawData, err := proto.Marshal(tx.RawData)
+	assert.NoError(t, err)
+	id := sha256.Sum256(rawData)
+	return tx, id[:]
+}
+
+// newFakeTxParser serves srv on localhost and returns a TxParser connected to it.
+func newFakeTxParser(t *testing.T, srv *fakeWalletServer) *TxParser {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	gs := grpc.NewServer()
+	api.RegisterWalletServer(gs, srv)
+	go gs.Serve(lis)
+	t.Cleanup(gs.Stop)
+
+	cli := client.NewGrpcClient(lis.Addr().String())
+	assert.NoError(t, cli.Start(client.GRPCInsecure()))
+	t.Cleanup(cli.Stop)
+	return &TxParser{cli: cli}
+}
+
+func TestConfirmationTracker(t *testing.T) {
+	ctx := context.Background()
+	from, to := "TJRabPrwbZy45sbavfcjinPJC18kjpRTv8", "TXYZopYRdj2D9XRtbG411XZZ3kM5VkAeBf"
+	tx, txID := newFakeTransferTx(t, from, to)
+	txHash := hex.EncodeToString(txID)
+
+	srv := &fakeWalletServer{headTime: 1700000000000, solidNum: 90, tx: tx}
+	ct := newFakeTxParser(t, srv).ConfirmationTracker(ConfirmationOptions{PollInterval: 10 * time.Millisecond})
+
+	t.Run("expired", func(t *testing.T) {
+		srv.set(func(s *fakeWalletServer) { s.headTime = 1700000063000 })
+		_, err := ct.Wait(ctx, txHash, tx.RawData.Expiration)
+		assert.ErrorIs(t, err, ErrTxExpired)
+	})
+
+	t.Run("solidified", func(t *testing.T) {
+		srv.set(func(s *fakeWalletServer) {
+			s.txInfo = &core.TransactionInfo{
+				Id:             txID,
+				Fee:            1100,
+				BlockNumber:    100,
+				BlockTimeStamp: 1700000003000,
+				Receipt:        &core.ResourceReceipt{NetUsage: 268},
+			}
+		})
+		go func() {
+			time.Sleep(30 * time.Millisecond)
+			srv.set(func(s *fakeWalletServer) { s.solidNum = 100 })
+		}()
+		ptx, err := ct.Wait(ctx, txHash)
+		assert.NoError(t, err)
+		assert.Equal(t, txHash, ptx.TxHash)
+		assert.Equal(t, int64(100), ptx.Block)
+		assert.Equal(t, "success", ptx.Status)
+		assert.Equal(t, from, ptx.From)
+		assert.Equal(t, to, ptx.To)
+		assert.Equal(t, SunPerTRX, ptx.Value)
+		assert.Equal(t, int64(1100), ptx.Fee.Fee)
+	})
+}
+
+func TestConfirmationTrackerPartialOptions(t *testing.T) {
+	ct := (&TxParser{}).ConfirmationTracker(ConfirmationOptions{})
+	assert.Equal(t, DefaultConfirmationOptions, ct.opts)
+}
@@ -1,6 +1,29 @@
 package utron
 
+import "time"
+
 const (
 	SunPerTRX   int64 = 1000000 // 1 TRX = 1,000,000 Sun
 	TRXDecimals uint8 = 6
 )
+
+// TxExpiration is the default expiration of the transactions created by a node, after the head block time.
+// https://developers.tron.network/docs/tron-protocol-transaction#transaction-expiration
+const TxExpiration = 60 * time.Second
+
+// BIP-0044 derivation paths, the coin types are registered in https://github.com/satoshilabs/slips/blob/master/slip-0044.md
+const (
+	// DerivationPathFormat is the BIP-0044 path used by TronLink, the placeholder is the account index.
+	DerivationPathFormat = "m/44'/195'/%d'/0/0"
+
+	// AccountPathFormat is the BIP-0044 account level path, the placeholder is the account index.
+	// Extended public keys exported at this level derive addresses with the relative path "0/{addressIndex}".
+	// NOTE: TronLink changes the hardened account level for each account, so only its first account (0/0)
+	// can be derived from the extended public key of account 0.
+	AccountPathFormat = "m/44'/195'/%d'"
+
+	// AddressPathFormat is the BIP-0044 path of the addresses derived from the extended public key of an account,
+	// the placeholders are the account index and the address index.
+	// Use it with CreateWalletAccountFromMnemonicPath to get the private key of an address derived from the xpub.
+	AddressPathFormat = "m/44'/195'/%d'/0/%d"
+)
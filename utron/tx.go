@@ -1,13 +1,16 @@
 package utron
 
 import (
+	"bytes"
 	"context"
 	"crypto/tls"
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"iter"
 	"slices"
 
+	"github.com/15ho/wallet-utils-go/internal/blockrange"
 	"github.com/15ho/wallet-utils-go/internal/zlog"
 	"github.com/fbsobreira/gotron-sdk/pkg/address"
 	"github.com/fbsobreira/gotron-sdk/pkg/client"
@@ -44,8 +47,16 @@ type ParsedTx struct {
 	TxType    int32        // transaction type // https://github.com/tronprotocol/java-tron/blob/develop/protocol/src/main/protos/core/Tron.proto#L338
 }
 
+var (
+	// ErrTxNotFound is returned by ParseTransaction for unknown and not yet included transactions.
+	ErrTxNotFound = errors.New("transaction not found")
+	// ErrBlockNotFound is returned by ParseBlockByNumber for the blocks above the head of the node.
+	ErrBlockNotFound = errors.New("block not found")
+)
+
 type TxParser struct {
-	cli *client.GrpcClient
+	cli    *client.GrpcClient
+	strict bool
 }
 
 func newTxParser(endpoint string, opts ...grpc.DialOption) (tp *TxParser, cleanup func(), err error) {
@@ -79,29 +90,138 @@ func NewTxParserWithXToken(endpoint, token string) (tp *TxParser, cleanup func()
 		grpc.WithPerRPCCredentials(auth{token}))
 }
 
-func (tp *TxParser) ParseBlock(ctx context.Context) ([]*ParsedTx, error) {
-	block, err := tp.cli.GetNowBlock()
+// maxBlockMsgSize raises the 4MB default limit of grpc to 32MB, full blocks and their transaction infos are larger.
+var maxBlockMsgSize = grpc.MaxCallRecvMsgSize(32 << 20)
+
+// ParsedBlock is a parsed block, the transactions failing to parse are in Failed.
+type ParsedBlock struct {
+	Block  int64 // block height
+	Txs    []*ParsedTx
+	Failed []*TxParseError
+}
+
+// TxParseError is listed in ParsedBlock.Failed, TxHash is the hex txid.
+type TxParseError = blockrange.TxParseError
+
+// SetStrict fails the parsing of a block on its first transaction failing to parse (or missing its info),
+// the error wraps the *TxParseError.
+func (tp *TxParser) SetStrict(strict bool) {
+	tp.strict = strict
+}
+
+// ParseBlock parses the latest block.
+func (tp *TxParser) ParseBlock(ctx context.Context) (*ParsedBlock, error) {
+	block, err := tp.cli.Client.GetNowBlock2(ctx, new(api.EmptyMessage), maxBlockMsgSize)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get block: %w", err)
 	}
+	return tp.parseBlock(ctx, block)
+}
+
+// ParseBlockByNumber parses the block of the given height.
+func (tp *TxParser) ParseBlockByNumber(ctx context.Context, blockNumber int64) (*ParsedBlock, error) {
+	block, err := tp.cli.Client.GetBlockByNum2(ctx, &api.NumberMessage{Num: blockNumber}, maxBlockMsgSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block by number(%d): %w", blockNumber, err)
+	}
+	// the node returns an empty block above its head
+	if block.GetBlockHeader().GetRawData() == nil {
+		return nil, fmt.Errorf("block %d: %w", blockNumber, ErrBlockNotFound)
+	}
+	return tp.parseBlock(ctx, block)
+}
+
+// ParseBlockRange parses the blocks from startBlock to endBlock (inclusive), workersOption blocks at a time (8 by default),
+// and yields them in block order. A block failing to parse is yielded with its error and the range goes on,
+// the iteration stops with ctx.Err() when ctx is done.
+func (tp *TxParser) ParseBlockRange(ctx context.Context, startBlock, endBlock int64, workersOption ...int) iter.Seq2[*ParsedBlock, error] {
+	var workers int
+	if len(workersOption) > 0 {
+		workers = workersOption[0]
+	}
+	return blockrange.Parse(ctx, startBlock, endBlock, workers, func(ctx context.Context, blockNumber int64) (*ParsedBlock, error) {
+		pb, err := tp.ParseBlockByNumber(ctx, blockNumber)
+		if err != nil {
+			return &ParsedBlock{Block: blockNumber}, err
+		}
+		return pb, nil
+	})
+}
 
-	blockInfo, err := tp.cli.GetBlockInfoByNum(block.BlockHeader.RawData.Number)
+func (tp *TxParser) parseBlock(ctx context.Context, block *api.BlockExtention) (*ParsedBlock, error) {
+	blockNumber := block.GetBlockHeader().GetRawData().GetNumber()
+	blockInfo, err := tp.cli.Client.GetTransactionInfoByBlockNum(ctx, &api.NumberMessage{Num: blockNumber}, maxBlockMsgSize)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get block info: %w", err)
 	}
+	txInfos := make(map[string]*core.TransactionInfo, len(blockInfo.GetTransactionInfo()))
+	slices.Values(blockInfo.GetTransactionInfo())(func(txInfo *core.TransactionInfo) bool {
+		txInfos[hex.EncodeToString(txInfo.Id)] = txInfo
+		return true
+	})
 
-	parsedTxs := make([]*ParsedTx, 0, len(block.Transactions))
+	pb := &ParsedBlock{
+		Block: blockNumber,
+		Txs:   make([]*ParsedTx, 0, len(block.Transactions)),
+	}
+	var strictErr error
 	slices.All(block.Transactions)(func(idx int, txExt *api.TransactionExtention) bool {
-		ptx, err := tp.parseTx(txExt.Transaction, blockInfo.TransactionInfo[idx])
+		txHash := hex.EncodeToString(txExt.Txid)
+		var (
+			ptx *ParsedTx
+			err error
+		)
+		txInfo, ok := txInfos[txHash]
+		if !ok {
+			err = errors.New("transaction info not found")
+		} else {
+			ptx, err = tp.parseTx(txExt.Transaction, txInfo)
+		}
 		if err != nil {
-			zlog.Error("parseTxWithTxInfo", zap.Error(err), zap.String("txHash", hex.EncodeToString(txExt.Txid)))
+			txErr := &TxParseError{Index: idx, TxHash: txHash, Err: err}
+			if tp.strict {
+				strictErr = fmt.Errorf("block %d: %w", blockNumber, txErr)
+				return false
+			}
+			zlog.Error("parseTxWithTxInfo", zap.Error(err), zap.Int64("block", blockNumber), zap.String("txHash", txHash))
+			pb.Failed = append(pb.Failed, txErr)
 			return true
 		}
-		parsedTxs = append(parsedTxs, ptx)
+		pb.Txs = append(pb.Txs, ptx)
 		return true
 	})
+	if strictErr != nil {
+		return nil, strictErr
+	}
+	return pb, nil
+}
+
+// ParseTransaction parses an included transaction with its info, the result is the same as the transaction's entry of ParseBlock.
+func (tp *TxParser) ParseTransaction(ctx context.Context, txHash string) (*ParsedTx, error) {
+	txID, err := hex.DecodeString(txHash)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tx hash: %w", err)
+	}
+	txInfo, err := tp.cli.Client.GetTransactionInfoById(ctx, &api.BytesMessage{Value: txID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction info: %w", err)
+	}
+	if !bytes.Equal(txInfo.GetId(), txID) {
+		return nil, fmt.Errorf("%w: %s", ErrTxNotFound, txHash)
+	}
+	return tp.parseTxByInfo(ctx, txInfo)
+}
 
-	return parsedTxs, nil
+// parseTxByInfo reads the transaction of a transaction info.
+func (tp *TxParser) parseTxByInfo(ctx context.Context, txInfo *core.TransactionInfo) (*ParsedTx, error) {
+	tx, err := tp.cli.Client.GetTransactionById(ctx, &api.BytesMessage{Value: txInfo.Id})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+	if tx.GetRawData() == nil {
+		return nil, fmt.Errorf("%w: %x", ErrTxNotFound, txInfo.Id)
+	}
+	return tp.parseTx(tx, txInfo)
 }
 
 func (tp *TxParser) parseTx(tx *core.Transaction, txInfo *core.TransactionInfo) (ptx *ParsedTx, err error) {
@@ -1,9 +1,13 @@
 package utron
 
 import (
+	"context"
+	"encoding/hex"
 	"slices"
 	"testing"
 
+	"github.com/fbsobreira/gotron-sdk/pkg/proto/core"
+
 	"github.com/stretchr/testify/assert"
 )
 
@@ -55,3 +59,115 @@ func TestTxParser(t *testing.T) {
 	})
 
 }
+
+func TestParseTransaction(t *testing.T) {
+	from, to := "TJRabPrwbZy45sbavfcjinPJC18kjpRTv8", "TXYZopYRdj2D9XRtbG411XZZ3kM5VkAeBf"
+	tx, txID := newFakeTransferTx(t, from, to)
+	txHash := hex.EncodeToString(txID)
+	srv := &fakeWalletServer{tx: tx}
+	tp := newFakeTxParser(t, srv)
+
+	_, err := tp.ParseTransaction(t.Context(), txHash)
+	assert.ErrorIs(t, err, ErrTxNotFound)
+
+	srv.set(func(s *fakeWalletServer) {
+		s.txInfo = &core.TransactionInfo{
+			Id:             txID,
+			Fee:            1100,
+			BlockNumber:    100,
+			BlockTimeStamp: 1700000003000,
+			Receipt:        &core.ResourceReceipt{NetUsage: 268},
+		}
+	})
+	ptx, err := tp.ParseTransaction(t.Context(), txHash)
+	assert.NoError(t, err)
+	assert.Equal(t, txHash, ptx.TxHash)
+	assert.Equal(t, int64(100), ptx.Block)
+	assert.Equal(t, int64(1700000003000), ptx.Timestamp)
+	assert.Equal(t, from, ptx.From)
+	assert.Equal(t, to, ptx.To)
+	assert.Equal(t, SunPerTRX, ptx.Value)
+	assert.Equal(t, int64(268), ptx.Fee.BandwidthUsed)
+
+	_, err = tp.ParseTransaction(t.Context(), "invalid")
+	assert.Error(t, err)
+}
+
+func TestParseBlockByNumber(t *testing.T) {
+	ctx := t.Context()
+	from, to := "TJRabPrwbZy45sbavfcjinPJC18kjpRTv8", "TXYZopYRdj2D9XRtbG411XZZ3kM5VkAeBf"
+	tx, txID := newFakeTransferTx(t, from, to)
+	srv := &fakeWalletServer{
+		headNum: 102,
+		tx:      tx,
+		txInfo: &core.TransactionInfo{
+			Id:          txID,
+			BlockNumber: 101,
+			Receipt:     &core.ResourceReceipt{},
+		},
+	}
+	tp := newFakeTxParser(t, srv)
+
+	pb, err := tp.ParseBlockByNumber(ctx, 101)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(101), pb.Block)
+	assert.Len(t, pb.Txs, 1)
+	assert.Equal(t, hex.EncodeToString(txID), pb.Txs[0].TxHash)
+	assert.Equal(t, int64(101), pb.Txs[0].Block)
+	assert.Empty(t, pb.Failed)
+
+	pb, err = tp.ParseBlockByNumber(ctx, 100)
+	assert.NoError(t, err)
+	assert.Empty(t, pb.Txs)
+
+	_, err = tp.ParseBlockByNumber(ctx, 103)
+	assert.ErrorIs(t, err, ErrBlockNotFound)
+
+	t.Run("range", func(t *testing.T) {
+		var blocks []int64
+		for pb, err := range tp.ParseBlockRange(ctx, 100, 103, 2) {
+			blocks = append(blocks, pb.Block)
+			switch pb.Block {
+			case 101:
+				assert.NoError(t, err)
+				assert.Len(t, pb.Txs, 1)
+			case 103:
+				assert.ErrorIs(t, err, ErrBlockNotFound)
+			default:
+				assert.NoError(t, err)
+				assert.Empty(t, pb.Txs)
+			}
+		}
+		assert.Equal(t, []int64{100, 101, 102, 103}, blocks)
+
+		cancelCtx, cancel := context.WithCancel(ctx)
+		cancel()
+		var errs []error
+		for _, err := range tp.ParseBlockRange(cancelCtx, 100, 102) {
+			errs = append(errs, err)
+		}
+		assert.Len(t, errs, 1)
+		assert.ErrorIs(t, errs[0], context.Canceled)
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		srv.set(func(s *fakeWalletServer) { s.noInfos = true })
+		defer srv.set(func(s *fakeWalletServer) { s.noInfos = false })
+
+		pb, err := tp.ParseBlockByNumber(ctx, 101)
+		assert.NoError(t, err)
+		assert.Empty(t, pb.Txs)
+		if assert.Len(t, pb.Failed, 1) {
+			assert.Equal(t, 0, pb.Failed[0].Index)
+			assert.Equal(t, hex.EncodeToString(txID), pb.Failed[0].TxHash)
+		}
+
+		tp.SetStrict(true)
+		defer tp.SetStrict(false)
+		_, err = tp.ParseBlockByNumber(ctx, 101)
+		var txErr *TxParseError
+		if assert.ErrorAs(t, err, &txErr) {
+			assert.Equal(t, hex.EncodeToString(txID), txErr.TxHash)
+		}
+	})
+}
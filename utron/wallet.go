@@ -2,7 +2,6 @@ package utron
 
 import (
 	"context"
-	"crypto/ecdsa"
 	"crypto/sha256"
 	"crypto/tls"
 	"encoding/hex"
@@ -11,6 +10,10 @@ import (
 	"slices"
 	"strings"
 
+	"github.com/15ho/wallet-utils-go/bip32"
+	"github.com/15ho/wallet-utils-go/bip39"
+	"github.com/15ho/wallet-utils-go/keystore"
+	"github.com/15ho/wallet-utils-go/signer"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -36,27 +39,96 @@ func CreateWalletAccount() (privateKeyHex, address string, err error) {
 	return
 }
 
-func WalletAddressFromPrivateKey(privateKeyHex string) (address string, err error) {
-	pk, err := crypto.ToECDSA(common.FromHex(privateKeyHex))
+// CreateWalletAccountFromMnemonic derives the account at m/44'/195'/{accountIndex}'/0/0, the same as TronLink.
+// The passphrase is the optional BIP-0039 passphrase, pass "" if none.
+func CreateWalletAccountFromMnemonic(mnemonic, passphrase string, accountIndex uint32) (privateKeyHex, address string, err error) {
+	return CreateWalletAccountFromMnemonicPath(mnemonic, passphrase, fmt.Sprintf(DerivationPathFormat, accountIndex))
+}
+
+// CreateWalletAccountFromMnemonicPath derives the account at a BIP-0032 path, e.g. fmt.Sprintf(AddressPathFormat, 0, 5)
+// for the address derived from the extended public key of account 0 with the relative path "0/5".
+func CreateWalletAccountFromMnemonicPath(mnemonic, passphrase, path string) (privateKeyHex, address string, err error) {
+	if err = bip39.Validate(mnemonic); err != nil {
+		err = fmt.Errorf("invalid mnemonic: %w", err)
+		return
+	}
+	seed := bip39.CreateSeedFromMnemonic(mnemonic, passphrase)
+	masterKey, err := bip32.NewMasterKey(seed)
+	if err != nil {
+		err = fmt.Errorf("new master key: %w", err)
+		return
+	}
+	key, err := masterKey.DerivePath(path)
 	if err != nil {
 		return
 	}
+	pk, err := key.ECPrivKey()
+	if err != nil {
+		return
+	}
+	privateKeyHex = hexutil.Encode(crypto.FromECDSA(pk))
 	address = tronaddr.PubkeyToAddress(pk.PublicKey).String()
 	return
 }
 
-type WalletClient struct {
-	cli *client.GrpcClient
+// ExtendedPublicKeyFromMnemonic returns the extended public key (xpub) of the account at m/44'/195'/{account}'.
+// It can be given to a watch-only service to derive addresses with WalletAddressFromExtendedPublicKey.
+func ExtendedPublicKeyFromMnemonic(mnemonic, passphrase string, account uint32) (xpub string, err error) {
+	if err = bip39.Validate(mnemonic); err != nil {
+		err = fmt.Errorf("invalid mnemonic: %w", err)
+		return
+	}
+	seed := bip39.CreateSeedFromMnemonic(mnemonic, passphrase)
+	masterKey, err := bip32.NewMasterKey(seed)
+	if err != nil {
+		err = fmt.Errorf("new master key: %w", err)
+		return
+	}
+	key, err := masterKey.DerivePath(fmt.Sprintf(AccountPathFormat, account))
+	if err != nil {
+		return
+	}
+	xpub = key.Neuter().String()
+	return
+}
 
-	privateKey *ecdsa.PrivateKey
-	account    string
+// WalletAddressFromExtendedPublicKey derives the address at a non-hardened path relative to the extended public key,
+// no private key is needed. For an account level xpub (m/44'/195'/0'), the path "0/5" gives the address of m/44'/195'/0'/0/5.
+func WalletAddressFromExtendedPublicKey(xpub, path string) (address string, err error) {
+	key, err := bip32.ParseExtendedKey(xpub)
+	if err != nil {
+		err = fmt.Errorf("parse extended key: %w", err)
+		return
+	}
+	key, err = key.Neuter().DerivePath(path)
+	if err != nil {
+		return
+	}
+	pub, err := key.ECPubKey()
+	if err != nil {
+		return
+	}
+	address = tronaddr.PubkeyToAddress(*pub).String()
+	return
 }
 
-func newWalletClient(endpoint string, privateKeyHex string, opts ...grpc.DialOption) (wc *WalletClient, cleanup func(), err error) {
+func WalletAddressFromPrivateKey(privateKeyHex string) (address string, err error) {
 	pk, err := crypto.ToECDSA(common.FromHex(privateKeyHex))
 	if err != nil {
 		return
 	}
+	address = tronaddr.PubkeyToAddress(pk.PublicKey).String()
+	return
+}
+
+type WalletClient struct {
+	cli *client.GrpcClient
+
+	signer  signer.Secp256k1Signer
+	account string
+}
+
+func newWalletClient(endpoint string, s signer.Secp256k1Signer, opts ...grpc.DialOption) (wc *WalletClient, cleanup func(), err error) {
 	cli := client.NewGrpcClient(endpoint)
 	if err = cli.Start(opts...); err != nil {
 		return
@@ -65,30 +137,117 @@ func newWalletClient(endpoint string, privateKeyHex string, opts ...grpc.DialOpt
 		cli.Stop()
 	}
 	wc = &WalletClient{
-		cli:        cli,
-		privateKey: pk,
-		account:    tronaddr.PubkeyToAddress(pk.PublicKey).String(),
+		cli:     cli,
+		signer:  s,
+		account: tronaddr.PubkeyToAddress(*s.PublicKey()).String(),
 	}
 	return
 }
 
 func NewWalletClient(endpoint, privateKeyHex string) (wc *WalletClient, cleanup func(), err error) {
-	return newWalletClient(endpoint, privateKeyHex, client.GRPCInsecure())
+	s, err := signer.NewLocalSecp256k1SignerFromHex(privateKeyHex)
+	if err != nil {
+		return
+	}
+	return NewWalletClientFromSigner(endpoint, s)
 }
 
 func NewWalletClientWithBasicAuth(endpoint, token, privateKeyHex string) (wc *WalletClient, cleanup func(), err error) {
-	return newWalletClient(endpoint, privateKeyHex, grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{})),
+	s, err := signer.NewLocalSecp256k1SignerFromHex(privateKeyHex)
+	if err != nil {
+		return
+	}
+	return NewWalletClientFromSignerWithBasicAuth(endpoint, token, s)
+}
+
+func NewWalletClientWithXToken(endpoint, token, privateKeyHex string) (wc *WalletClient, cleanup func(), err error) {
+	s, err := signer.NewLocalSecp256k1SignerFromHex(privateKeyHex)
+	if err != nil {
+		return
+	}
+	return NewWalletClientFromSignerWithXToken(endpoint, token, s)
+}
+
+// NewWalletClientFromSigner creates a WalletClient whose transactions are signed by s,
+// the private key is never touched by the WalletClient.
+func NewWalletClientFromSigner(endpoint string, s signer.Secp256k1Signer) (wc *WalletClient, cleanup func(), err error) {
+	return newWalletClient(endpoint, s, client.GRPCInsecure())
+}
+
+func NewWalletClientFromSignerWithBasicAuth(endpoint, token string, s signer.Secp256k1Signer) (wc *WalletClient, cleanup func(), err error) {
+	return newWalletClient(endpoint, s, grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{})),
 		grpc.WithPerRPCCredentials(basicAuth{
 			username: endpoint,
 			password: token,
 		}))
 }
 
-func NewWalletClientWithXToken(endpoint, token, privateKeyHex string) (wc *WalletClient, cleanup func(), err error) {
-	return newWalletClient(endpoint, privateKeyHex, grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{})),
+func NewWalletClientFromSignerWithXToken(endpoint, token string, s signer.Secp256k1Signer) (wc *WalletClient, cleanup func(), err error) {
+	return newWalletClient(endpoint, s, grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{})),
 		grpc.WithPerRPCCredentials(auth{token}))
 }
 
+// NewWalletClientFromKeystore opens a keystore v3 JSON file with the passphrase, see keystore.WriteKeyFile.
+func NewWalletClientFromKeystore(endpoint, keystorePath, passphrase string) (wc *WalletClient, cleanup func(), err error) {
+	s, err := readKeystore(keystorePath, passphrase)
+	if err != nil {
+		return
+	}
+	return NewWalletClientFromSigner(endpoint, s)
+}
+
+func NewWalletClientFromKeystoreWithBasicAuth(endpoint, token, keystorePath, passphrase string) (wc *WalletClient, cleanup func(), err error) {
+	s, err := readKeystore(keystorePath, passphrase)
+	if err != nil {
+		return
+	}
+	return NewWalletClientFromSignerWithBasicAuth(endpoint, token, s)
+}
+
+func NewWalletClientFromKeystoreWithXToken(endpoint, token, keystorePath, passphrase string) (wc *WalletClient, cleanup func(), err error) {
+	s, err := readKeystore(keystorePath, passphrase)
+	if err != nil {
+		return
+	}
+	return NewWalletClientFromSignerWithXToken(endpoint, token, s)
+}
+
+// signTx signs the transaction id (sha256 of the raw data) with the signer and appends the signature,
+// the signature must recover to the public key of the signer. A signer.TronTxSigner is given the raw data.
+func (wc *WalletClient) signTx(ctx context.Context, tx *core.Transaction) error {
+	rawData, err := proto.Marshal(tx.GetRawData())
+	if err != nil {
+		return fmt.Errorf("marshal tx raw data: %w", err)
+	}
+	txID := sha256.Sum256(rawData)
+	var signature []byte
+	if ts, ok := wc.signer.(signer.TronTxSigner); ok {
+		signature, err = ts.SignTronTx(ctx, rawData)
+	} else {
+		signature, err = wc.signer.SignDigest(ctx, txID[:])
+	}
+	if err != nil {
+		return err
+	}
+	publicKey, err := crypto.SigToPub(txID[:], signature)
+	if err != nil {
+		return fmt.Errorf("recover signature public key: %w", err)
+	}
+	if crypto.PubkeyToAddress(*publicKey) != crypto.PubkeyToAddress(*wc.signer.PublicKey()) {
+		return signer.ErrSignerMismatch
+	}
+	tx.Signature = append(tx.Signature, signature)
+	return nil
+}
+
+func readKeystore(keystorePath, passphrase string) (*signer.LocalSecp256k1Signer, error) {
+	privateKey, err := keystore.ReadKeyFile(keystorePath, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("open keystore: %w", err)
+	}
+	return signer.NewLocalSecp256k1SignerFromBytes(privateKey)
+}
+
 type Gas struct {
 	Total int64 // = Bandwidth * BandwidthUnitPrice + Energy + EnergyUnitPrice
 	// or, = CreateAccountFee + CreateAccountBandwidthFee
@@ -148,13 +307,11 @@ func (wc *WalletClient) EstimateGasTransferTRX(ctx context.Context, to string, a
 			err = fmt.Errorf("create transfer tx error: %w", terr)
 			return
 		}
-		signature, terr := crypto.Sign(txExt.Txid, wc.privateKey)
-		if terr != nil {
+		tx := txExt.Transaction
+		if terr = wc.signTx(ctx, tx); terr != nil {
 			err = fmt.Errorf("sign error: %w", terr)
 			return
 		}
-		tx := txExt.Transaction
-		tx.Signature = append(tx.Signature, signature)
 		txBytes, terr := proto.Marshal(tx)
 		if terr != nil {
 			err = fmt.Errorf("marshal tx error: %w", terr)
@@ -207,13 +364,11 @@ func (wc *WalletClient) EstimateGasTransferTRX(ctx context.Context, to string, a
 		err = fmt.Errorf("create transfer tx error: %w", err)
 		return
 	}
-	signature, err := crypto.Sign(txExt.Txid, wc.privateKey)
-	if err != nil {
+	tx := txExt.Transaction
+	if err = wc.signTx(ctx, tx); err != nil {
 		err = fmt.Errorf("sign error: %w", err)
 		return
 	}
-	tx := txExt.Transaction
-	tx.Signature = append(tx.Signature, signature)
 	txBytes, err := proto.Marshal(tx)
 	if err != nil {
 		err = fmt.Errorf("marshal tx error: %w", err)
@@ -236,12 +391,10 @@ func (wc *WalletClient) TransferTRX(ctx context.Context, to string, amount int64
 		err = fmt.Errorf("create transfer tx error: %w", err)
 		return
 	}
-	signature, err := crypto.Sign(txExt.Txid, wc.privateKey)
-	if err != nil {
+	if err = wc.signTx(ctx, txExt.Transaction); err != nil {
 		err = fmt.Errorf("sign error: %w", err)
 		return
 	}
-	txExt.Transaction.Signature = append(txExt.Transaction.Signature, signature)
 	ret, err := wc.cli.Broadcast(txExt.GetTransaction())
 	if err != nil {
 		err = fmt.Errorf("broadcast trx error: %v", err)
@@ -310,12 +463,10 @@ func (wc *WalletClient) EstimateGasTransferTRC20Token(ctx context.Context, token
 		err = fmt.Errorf("create trc20 call tx error: %w", err)
 		return
 	}
-	signature, err := crypto.Sign(txExt.Txid, wc.privateKey)
-	if err != nil {
+	if err = wc.signTx(ctx, txExt.Transaction); err != nil {
 		err = fmt.Errorf("sign error: %w", err)
 		return
 	}
-	txExt.Transaction.Signature = append(txExt.Transaction.Signature, signature)
 	txBytes, err := proto.Marshal(txExt.Transaction)
 	if err != nil {
 		err = fmt.Errorf("marshal tx error: %w", err)
@@ -372,18 +523,10 @@ func (wc *WalletClient) EstimateGasTransferTRC20TokenV2(ctx context.Context, tok
 	tx := txExt.Transaction
 	tx.RawData.FeeLimit = feeLimit
 	tx.Ret = nil
-	txRawDataBytes, err := proto.Marshal(tx.RawData)
-	if err != nil {
-		err = fmt.Errorf("marshal transaction raw data: %w", err)
-		return
-	}
-	hash := sha256.Sum256(txRawDataBytes)
-	signature, err := crypto.Sign(hash[:], wc.privateKey)
-	if err != nil {
+	if err = wc.signTx(ctx, tx); err != nil {
 		err = fmt.Errorf("sign error: %w", err)
 		return
 	}
-	tx.Signature = append(tx.Signature, signature)
 	txBytes, err := proto.Marshal(tx)
 	if err != nil {
 		err = fmt.Errorf("marshal tx error: %w", err)
@@ -410,12 +553,10 @@ func (wc *WalletClient) TransferTRC20Token(ctx context.Context, tokenAddress, to
 		err = fmt.Errorf("create trc20 call tx error: %w", err)
 		return
 	}
-	signature, err := crypto.Sign(txExt.Txid, wc.privateKey)
-	if err != nil {
+	if err = wc.signTx(ctx, txExt.Transaction); err != nil {
 		err = fmt.Errorf("sign error: %w", err)
 		return
 	}
-	txExt.Transaction.Signature = append(txExt.Transaction.Signature, signature)
 	ret, err := wc.cli.Broadcast(txExt.GetTransaction())
 	if err != nil {
 		err = fmt.Errorf("broadcast trx error: %v", err)
@@ -1,9 +1,17 @@
 package utron
 
 import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"errors"
+	"fmt"
 	"math/big"
+	"strings"
 	"testing"
 
+	"github.com/15ho/wallet-utils-go/signer"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -17,6 +25,117 @@ func TestCreateWalletAccount(t *testing.T) {
 	t.Logf("address2: %s", address2)
 }
 
+func TestCreateWalletAccountFromMnemonic(t *testing.T) {
+	// addresses generated by TronLink
+	mnemonic := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	for _, tc := range []struct {
+		accountIndex uint32
+		address      string
+	}{
+		{0, "TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH"},
+	} {
+		privateKeyHex, address, err := CreateWalletAccountFromMnemonic(mnemonic, "", tc.accountIndex)
+		assert.NoError(t, err)
+		assert.Equal(t, tc.address, address)
+
+		address2, err := WalletAddressFromPrivateKey(privateKeyHex)
+		assert.NoError(t, err)
+		assert.Equal(t, tc.address, address2)
+	}
+
+	_, address, err := CreateWalletAccountFromMnemonic(mnemonic, "TREZOR", 0)
+	assert.NoError(t, err)
+	assert.NotEqual(t, "TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH", address)
+
+	_, _, err = CreateWalletAccountFromMnemonic("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon", "", 0)
+	assert.Error(t, err)
+}
+
+func TestWalletAddressFromExtendedPublicKey(t *testing.T) {
+	mnemonic := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	xpub, err := ExtendedPublicKeyFromMnemonic(mnemonic, "", 0)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(xpub, "xpub"))
+	// m/44'/195'/0'/0/{i}, the first one is the TronLink account 0,
+	// TronLink hardens the account level for the following accounts instead of the address index
+	for i := range uint32(5) {
+		address, err := WalletAddressFromExtendedPublicKey(xpub, fmt.Sprintf("0/%d", i))
+		assert.NoError(t, err)
+
+		privateKeyHex, expectAddress, err := CreateWalletAccountFromMnemonicPath(mnemonic, "", fmt.Sprintf(AddressPathFormat, 0, i))
+		assert.NoError(t, err)
+		assert.Equal(t, expectAddress, address)
+		keyAddress, err := WalletAddressFromPrivateKey(privateKeyHex)
+		assert.NoError(t, err)
+		assert.Equal(t, expectAddress, keyAddress)
+	}
+	_, tronLinkAddress, err := CreateWalletAccountFromMnemonic(mnemonic, "", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, "TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH", tronLinkAddress)
+
+	_, _, err = CreateWalletAccountFromMnemonicPath(mnemonic, "", "m/44'/195'/invalid")
+	assert.Error(t, err)
+
+	_, err = WalletAddressFromExtendedPublicKey(xpub, "0'/0")
+	assert.Error(t, err)
+
+	_, err = WalletAddressFromExtendedPublicKey("xpub-invalid", "0/0")
+	assert.Error(t, err)
+}
+
+// corruptSigner returns signatures of another key.
+type corruptSigner struct {
+	*signer.LocalSecp256k1Signer
+	otherKey *ecdsa.PrivateKey
+}
+
+func (s corruptSigner) SignDigest(_ context.Context, digest []byte) ([]byte, error) {
+	return crypto.Sign(digest, s.otherKey)
+}
+
+// rawDataSigner signs the raw data of the transactions, SignDigest must not be called.
+type rawDataSigner struct {
+	*signer.LocalSecp256k1Signer
+}
+
+func (s rawDataSigner) SignDigest(context.Context, []byte) ([]byte, error) {
+	return nil, errors.New("unexpected digest signing")
+}
+
+func (s rawDataSigner) SignTronTx(ctx context.Context, rawData []byte) ([]byte, error) {
+	txID := sha256.Sum256(rawData)
+	return s.LocalSecp256k1Signer.SignDigest(ctx, txID[:])
+}
+
+func TestSignTx(t *testing.T) {
+	privateKeyHex, from, err := CreateWalletAccount()
+	assert.NoError(t, err)
+	_, to, err := CreateWalletAccount()
+	assert.NoError(t, err)
+	s, err := signer.NewLocalSecp256k1SignerFromHex(privateKeyHex)
+	assert.NoError(t, err)
+	tx, txID := newFakeTransferTx(t, from, to)
+
+	wc := &WalletClient{signer: s}
+	assert.NoError(t, wc.signTx(t.Context(), tx))
+	assert.Len(t, tx.Signature, 1)
+	publicKey, err := crypto.SigToPub(txID, tx.Signature[0])
+	assert.NoError(t, err)
+	assert.Equal(t, crypto.PubkeyToAddress(*s.PublicKey()), crypto.PubkeyToAddress(*publicKey))
+
+	tx, _ = newFakeTransferTx(t, from, to)
+	wc = &WalletClient{signer: rawDataSigner{s}}
+	assert.NoError(t, wc.signTx(t.Context(), tx))
+	assert.Len(t, tx.Signature, 1)
+
+	otherKey, err := crypto.GenerateKey()
+	assert.NoError(t, err)
+	tx, _ = newFakeTransferTx(t, from, to)
+	wc = &WalletClient{signer: corruptSigner{s, otherKey}}
+	assert.ErrorIs(t, wc.signTx(t.Context(), tx), signer.ErrSignerMismatch)
+	assert.Empty(t, tx.Signature)
+}
+
 func TestWalletClient(t *testing.T) {
 	wc, cleanup, err := NewWalletClient(TronTestnet, Acc1PrivateKeyHex)
 	assert.NoError(t, err)